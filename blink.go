@@ -0,0 +1,39 @@
+package spintron
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// blinkInterval is how long the persisted symbol stays shown, and hidden, in
+// each blink cycle.
+const blinkInterval = 100 * time.Millisecond
+
+// blinks reports whether the persisted symbol blinks before it's left steady:
+// PersistBlink is set and the line can be redrawn in place on a terminal.
+// Caller must already hold s.lock.
+func (s *Spinner) blinks() bool {
+	return s.PersistBlink > 0 && !s.static() && !s.PersistWithoutErase && (s.ForceTerminal || isRunningInTerminal(s.Writer))
+}
+
+// blink redraws the persisted line PersistBlink times with the symbol hidden
+// and shown again, with the cursor hidden meanwhile if HideCursor is set. The
+// line is left steady without its line break, which the caller writes.
+// Caller must already hold s.lock.
+func (s *Spinner) blink(symbol string, text string) {
+	shown := strings.TrimSuffix(s.persistedLine(symbol, text), "\n")
+	hidden := strings.TrimSuffix(s.persistedLine(strings.Repeat(" ", displayWidth(symbol)), text), "\n")
+
+	if s.hidesCursor() {
+		fmt.Fprint(s.out(), "\033[?25l")
+		defer fmt.Fprint(s.out(), "\033[?25h")
+	}
+	fmt.Fprint(s.out(), shown)
+	for i := 0; i < s.PersistBlink; i++ {
+		time.Sleep(blinkInterval)
+		fmt.Fprint(s.out(), hidden)
+		time.Sleep(blinkInterval)
+		fmt.Fprint(s.out(), shown)
+	}
+}
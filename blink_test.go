@@ -0,0 +1,34 @@
+package spintron
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPersistBlink(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{CharacterSet: []string{"-"}, Delay: time.Hour, HideCursor: true, PersistBlink: 2, SuccessSymbol: "+"})
+	s.Start()
+	waitFor(t, "the first frame", func() bool { return strings.Contains(buf.String(), "-") })
+	buf.Reset()
+	s.Succeed("done")
+
+	out := buf.String()
+	i := strings.Index(out, "\x1b[?25l")
+	if i < 0 || !strings.HasSuffix(out, "\x1b[?25h\n") {
+		t.Fatalf("output %q doesn't hide the cursor while blinking and show it again", out)
+	}
+	if got, want := out[i+len("\x1b[?25l"):len(out)-len("\x1b[?25h\n")], strings.Repeat("\r+ done\r  done", 2)+"\r+ done"; got != want {
+		t.Errorf("blinked %q, want 2 cycles %q", got, want)
+	}
+}
+
+func TestPersistBlinkNonTTY(t *testing.T) {
+	cleanEnv(t)
+	var buf syncBuffer
+	s := New(Options{Writer: &buf, PersistBlink: 2, SuccessSymbol: "+"})
+	s.Succeed("done")
+	if got := buf.String(); got != "\r+ done\n" {
+		t.Errorf("non-terminal got %q, want the line once", got)
+	}
+}
@@ -0,0 +1,45 @@
+package spintron
+
+import "time"
+
+// runCallback invokes a PreUpdate or PostUpdate callback and records how long
+// it took. With CallbackTimeout set, a callback running over it is reported to
+// DebugLog while it's still running. The lock is released while the callback
+// runs, so it can call the methods of the spinner, which means anything about
+// the spinner may have changed once runCallback returns.
+// Caller must already hold s.lock.
+func (s *Spinner) runCallback(name string, fn func(s *Spinner)) {
+	var watchdog *time.Timer
+	if s.CallbackTimeout > 0 {
+		timeout := s.CallbackTimeout
+		debugLog := s.DebugLog
+		watchdog = time.AfterFunc(timeout, func() {
+			if debugLog != nil {
+				debugLog("%s callback is taking longer than %s", name, timeout)
+			}
+		})
+	}
+
+	s.mu.Unlock()
+	start := time.Now()
+	fn(s)
+	elapsed := time.Since(start)
+	s.mu.Lock()
+
+	if watchdog != nil {
+		watchdog.Stop()
+	}
+	s.callbackTime += elapsed
+	s.callbackCalls++
+}
+
+// CallbackDuration returns the average time the PreUpdate and PostUpdate
+// callbacks took, or 0 if none ran yet.
+func (s *Spinner) CallbackDuration() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.callbackCalls == 0 {
+		return 0
+	}
+	return s.callbackTime / time.Duration(s.callbackCalls)
+}
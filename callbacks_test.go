@@ -0,0 +1,76 @@
+package spintron
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCallbackDuration(t *testing.T) {
+	var mu sync.Mutex
+	var logged []string
+	s, _ := newTestSpinner(t, Options{
+		Managed:         true,
+		CallbackTimeout: 5 * time.Millisecond,
+		DebugLog: func(format string, args ...interface{}) {
+			mu.Lock()
+			logged = append(logged, fmt.Sprintf(format, args...))
+			mu.Unlock()
+		},
+	})
+	s.PreUpdate = func(s *Spinner) { time.Sleep(20 * time.Millisecond) }
+	if d := s.CallbackDuration(); d != 0 {
+		t.Errorf("duration %v before any callback ran, want 0", d)
+	}
+	s.Start()
+	s.RenderNextFrame()
+	s.RenderNextFrame()
+	s.Stop()
+
+	if d := s.CallbackDuration(); d < 20*time.Millisecond || d > time.Second {
+		t.Errorf("average duration %v, want about 20ms", d)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !strings.Contains(strings.Join(logged, "\n"), "PreUpdate callback is taking longer than 5ms") {
+		t.Errorf("slow callback not reported: %q", logged)
+	}
+}
+
+func TestCallbacksCallSpinner(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Delay: time.Millisecond, CharacterSet: []string{"-"}})
+	n := 0
+	s.PreUpdate = func(s *Spinner) {
+		if !s.Active() {
+			t.Error("PreUpdate: spinner not active")
+		}
+	}
+	s.PostUpdate = func(s *Spinner) {
+		n++
+		s.SetText(fmt.Sprintf("step %d", n))
+		if err := s.Color("green"); err != nil {
+			t.Error(err)
+		}
+	}
+	s.Start()
+	waitFor(t, "a frame with the text set by PostUpdate", func() bool {
+		return strings.Contains(stripANSI(buf.String()), "- step 3")
+	})
+	s.Stop()
+}
+
+func TestPreUpdateStops(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"-"}})
+	s.PreUpdate = func(s *Spinner) { s.Stop() }
+	s.Start()
+	buf.Reset()
+	s.RenderNextFrame()
+	if s.Active() {
+		t.Error("spinner still active after PreUpdate stopped it")
+	}
+	if got := frames(buf.String()); len(got) != 0 {
+		t.Errorf("frames %q drawn after PreUpdate stopped the spinner", got)
+	}
+}
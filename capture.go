@@ -0,0 +1,50 @@
+package spintron
+
+import "strings"
+
+// captureFrame stores the given plain frame in the ring buffer of captured
+// frames.
+// Caller must already hold s.lock.
+func (s *Spinner) captureFrame(frame string) {
+	if s.CaptureFrames <= 0 {
+		return
+	}
+	frame = strings.TrimPrefix(frame, "\r")
+	if len(s.captured) < s.CaptureFrames {
+		s.captured = append(s.captured, frame)
+		return
+	}
+	s.captured[s.captureNext%len(s.captured)] = frame
+	s.captureNext = (s.captureNext + 1) % len(s.captured)
+}
+
+// CapturedFrames returns the most recently rendered frames, oldest first, up
+// to the number set with CaptureFrames.
+func (s *Spinner) CapturedFrames() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	frames := make([]string, 0, len(s.captured))
+	frames = append(frames, s.captured[s.captureNext:]...)
+	frames = append(frames, s.captured[:s.captureNext]...)
+	return frames
+}
+
+// LastOutput returns the last frame written out, uncolored and without the
+// carriage return it's drawn with, or "" once it's erased.
+func (s *Spinner) LastOutput() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return strings.TrimPrefix(s.lastOutput, "\r")
+}
+
+// Frames returns a copy of the character set the spinner animates, in the
+// order it's animated in.
+func (s *Spinner) Frames() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	frames := make([]string, len(s.chars))
+	for i := range frames {
+		frames[i] = s.charAt(i)
+	}
+	return frames
+}
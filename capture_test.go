@@ -0,0 +1,46 @@
+package spintron
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCapturedFrames(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Managed: true, CaptureFrames: 3, CharacterSet: []string{"a", "b", "c", "d", "e"}})
+	s.Start()
+	for i := 0; i < 5; i++ {
+		s.RenderNextFrame()
+	}
+	s.Stop()
+
+	if got, want := strings.Join(s.CapturedFrames(), " "), "c d e"; got != want {
+		t.Errorf("captured frames %q, want the last three %q", got, want)
+	}
+}
+
+func TestLastOutput(t *testing.T) {
+	withColor(t)
+	s, _ := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"a", "b"}, Text: "working"})
+	s.Start()
+	s.RenderNextFrame()
+	s.RenderNextFrame()
+	if got, want := s.LastOutput(), "b working"; got != want {
+		t.Errorf("LastOutput() = %q, want the uncolored frame %q", got, want)
+	}
+	s.Stop()
+	if got := s.LastOutput(); got != "" {
+		t.Errorf("LastOutput() = %q after Stop, want \"\"", got)
+	}
+}
+
+func TestFrames(t *testing.T) {
+	s := New(Options{CharacterSet: []string{"a", "b", "c"}})
+	frames := s.Frames()
+	if got := strings.Join(frames, " "); got != "a b c" {
+		t.Errorf("Frames() = %q, want the character set", got)
+	}
+	frames[0] = "x"
+	if got := strings.Join(s.Frames(), " "); got != "a b c" {
+		t.Errorf("Frames() = %q after changing the returned slice", got)
+	}
+}
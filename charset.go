@@ -0,0 +1,126 @@
+package spintron
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	randomMu sync.Mutex
+	random   = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// defaultDelay is the delay between frames of character sets without a
+// recommended one.
+const defaultDelay = 100 * time.Millisecond
+
+// CharSetDelays holds the delays the built-in character sets look right at, by
+// name. New and UpdateCharSet use them unless a delay was set explicitly, even
+// through the Delay field, and defaultDelay for character sets without one.
+var CharSetDelays = map[string]time.Duration{
+	"dots":                80 * time.Millisecond,
+	"line":                130 * time.Millisecond,
+	"simpleDots":          400 * time.Millisecond,
+	"simpleDotsScrolling": 200 * time.Millisecond,
+	"star":                70 * time.Millisecond,
+	"star2":               80 * time.Millisecond,
+	"flip":                70 * time.Millisecond,
+	"growVertical":        120 * time.Millisecond,
+	"growHorizontal":      120 * time.Millisecond,
+	"balloon":             140 * time.Millisecond,
+	"bounce":              120 * time.Millisecond,
+	"boxBounce":           120 * time.Millisecond,
+	"circle":              120 * time.Millisecond,
+	"toggle":              250 * time.Millisecond,
+	"arrow3":              120 * time.Millisecond,
+	"bouncingBar":         80 * time.Millisecond,
+	"bouncingBall":        80 * time.Millisecond,
+	"smiley":              200 * time.Millisecond,
+	"monkey":              300 * time.Millisecond,
+	"earth":               180 * time.Millisecond,
+	"moon":                80 * time.Millisecond,
+	"runner":              140 * time.Millisecond,
+	"pong":                80 * time.Millisecond,
+	"shark":               120 * time.Millisecond,
+	"christmas":           400 * time.Millisecond,
+	"point":               125 * time.Millisecond,
+	"aesthetic":           80 * time.Millisecond,
+}
+
+// recommendedDelay returns the delay of CharSetDelays for the built-in
+// character set with the same frames as cs, or defaultDelay.
+func recommendedDelay(cs []string) time.Duration {
+	for name, d := range CharSetDelays {
+		if sameFrames(CharSets[name], cs) {
+			return d
+		}
+	}
+	return defaultDelay
+}
+
+// sameFrames reports whether both character sets have the same frames.
+func sameFrames(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// applyRecommendedDelay sets the delay recommended for the character set,
+// unless a delay was set explicitly, through the options, the methods or the
+// Delay field.
+// Caller must already hold s.lock.
+func (s *Spinner) applyRecommendedDelay() {
+	if !s.explicitDelay && s.Delay == s.suggestedDelay && s.cycleDuration == 0 {
+		s.Delay = recommendedDelay(s.chars)
+		s.suggestedDelay = s.Delay
+	}
+}
+
+// CharSetByName returns a copy of the built-in character set with the given
+// name, such as "dots" or "bouncingBar", and whether there is one.
+func CharSetByName(name string) ([]string, bool) {
+	cs, ok := CharSets[name]
+	if !ok {
+		return nil, false
+	}
+	return append([]string(nil), cs...), true
+}
+
+// UpdateCharSetByName will change the current character set to the built-in
+// one with the given name, also while the spinner runs, or return an error if
+// there's none.
+func (s *Spinner) UpdateCharSetByName(name string) error {
+	cs, ok := CharSetByName(name)
+	if !ok {
+		return fmt.Errorf("character set %q: %w", name, errUnknownCharSet)
+	}
+	s.UpdateCharSet(cs)
+	return nil
+}
+
+// RandomCharSet returns one of the built-in character sets picked at random.
+func RandomCharSet() []string {
+	randomMu.Lock()
+	defer randomMu.Unlock()
+	return randomCharSet(random)
+}
+
+// randomCharSet picks a character set using the given source of randomness.
+// Names are sorted first so the same seed always picks the same set.
+func randomCharSet(r *rand.Rand) []string {
+	names := make([]string, 0, len(CharSets))
+	for name := range CharSets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return CharSets[names[r.Intn(len(names))]]
+}
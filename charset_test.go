@@ -0,0 +1,155 @@
+package spintron
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// setenv sets an environment variable for the duration of the test.
+func setenv(t testing.TB, key, value string) {
+	t.Helper()
+	old, ok := os.LookupEnv(key)
+	os.Setenv(key, value)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, old)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+// unsetenv unsets an environment variable for the duration of the test.
+func unsetenv(t testing.TB, key string) {
+	t.Helper()
+	old, ok := os.LookupEnv(key)
+	os.Unsetenv(key)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, old)
+		}
+	})
+}
+
+func TestRecommendedDelay(t *testing.T) {
+	s := New(Options{CharacterSet: CharSets["dots"]})
+	if s.Delay != 80*time.Millisecond {
+		t.Errorf("dots: delay %v, want 80ms", s.Delay)
+	}
+	s.UpdateCharSet(CharSets["line"])
+	if s.Delay != 130*time.Millisecond {
+		t.Errorf("line: delay %v, want 130ms", s.Delay)
+	}
+	s.UpdateCharSet([]string{"x", "y"})
+	if s.Delay != defaultDelay {
+		t.Errorf("custom set: delay %v, want %v", s.Delay, defaultDelay)
+	}
+}
+
+func TestRecommendedDelayKeepsExplicitDelay(t *testing.T) {
+	s := New(Options{Delay: 300 * time.Millisecond})
+	s.UpdateCharSet(CharSets["dots"])
+	if s.Delay != 300*time.Millisecond {
+		t.Errorf("Options.Delay: delay %v, want 300ms", s.Delay)
+	}
+
+	s = New(Options{})
+	s.UpdateSpeed(defaultDelay)
+	s.UpdateCharSet(CharSets["dots"])
+	if s.Delay != defaultDelay {
+		t.Errorf("UpdateSpeed: delay %v, want %v", s.Delay, defaultDelay)
+	}
+
+	s = New(Options{})
+	s.Delay = 500 * time.Millisecond
+	s.UpdateCharSet(CharSets["dots"])
+	if s.Delay != 500*time.Millisecond {
+		t.Errorf("Delay field: delay %v, want 500ms", s.Delay)
+	}
+}
+
+func TestRecommendedDelayReplacesThemeDelay(t *testing.T) {
+	setenv(t, "SPINNER_THEME", "minimal")
+
+	if s := New(Options{}); s.Delay != 400*time.Millisecond {
+		t.Errorf("theme alone: delay %v, want 400ms", s.Delay)
+	}
+	if s := New(Options{CharacterSet: CharSets["dots"]}); s.Delay != 80*time.Millisecond {
+		t.Errorf("theme and character set: delay %v, want 80ms", s.Delay)
+	}
+	if s := New(Options{CharacterSet: CharSets["dots"], Delay: time.Second}); s.Delay != time.Second {
+		t.Errorf("theme, character set and delay: delay %v, want 1s", s.Delay)
+	}
+}
+
+func TestCharSetByName(t *testing.T) {
+	cs, ok := CharSetByName("line")
+	if !ok || !sameFrames(cs, CharSets["line"]) {
+		t.Fatalf("CharSetByName(line) = %q, %t", cs, ok)
+	}
+	cs[0] = "x"
+	if CharSets["line"][0] == "x" {
+		t.Error("CharSetByName doesn't return a copy")
+	}
+	if _, ok := CharSetByName("nope"); ok {
+		t.Error("CharSetByName(nope) found a character set")
+	}
+}
+
+func TestRandomCharSetSeed(t *testing.T) {
+	first := New(Options{RandomCharSet: true, RandomSeed: 42})
+	for i := 0; i < 5; i++ {
+		s := New(Options{RandomCharSet: true, RandomSeed: 42})
+		if !sameFrames(s.chars, first.chars) {
+			t.Fatalf("seed 42 picked %q, then %q", first.chars, s.chars)
+		}
+	}
+
+	builtIn := false
+	for _, cs := range CharSets {
+		if sameFrames(cs, first.chars) {
+			builtIn = true
+		}
+	}
+	if !builtIn {
+		t.Errorf("picked %q, which isn't a built-in character set", first.chars)
+	}
+}
+
+func TestUpdateCharSetWhileRunning(t *testing.T) {
+	long := make([]string, 10)
+	for i := range long {
+		long[i] = string(rune('a' + i))
+	}
+	s, _ := newTestSpinner(t, Options{Delay: time.Millisecond, CharacterSet: long})
+	s.Start()
+	for i := 0; i < 100; i++ {
+		if i%2 == 0 {
+			s.UpdateCharSet(long)
+		} else if err := s.UpdateCharSetByName("line"); err != nil {
+			t.Fatal(err)
+		}
+		// the frame is within the set it was just swapped to
+		if i, char := s.CurrentFrame(); char == "" {
+			t.Fatalf("frame %d is out of the character set", i)
+		}
+		time.Sleep(100 * time.Microsecond)
+	}
+	s.Stop()
+
+	if err := s.UpdateCharSetByName("nope"); err == nil {
+		t.Error("UpdateCharSetByName(nope) = nil, want an error")
+	}
+}
+
+func TestCharacterSetName(t *testing.T) {
+	s := New(Options{CharacterSetName: "line"})
+	if got := s.Frames(); !sameFrames(got, CharSets["line"]) {
+		t.Errorf("frames %q, want the line character set", got)
+	}
+	def := New(Options{})
+	if got := New(Options{CharacterSetName: "nope"}).Frames(); !sameFrames(got, def.Frames()) {
+		t.Errorf("unknown name: frames %q, want the default ones", got)
+	}
+}
@@ -0,0 +1,289 @@
+package spintron
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+// withColor turns on colored output for the duration of the test, as the
+// color package turns it off when stdout isn't a terminal.
+func withColor(t testing.TB) {
+	t.Helper()
+	cleanEnv(t)
+	noColor := color.NoColor
+	color.NoColor = false
+	t.Cleanup(func() { color.NoColor = noColor })
+}
+
+func TestTwoToneOddCharSet(t *testing.T) {
+	withColor(t)
+	s, buf := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"a", "b", "c"}})
+	if err := s.TwoTone("red", "blue"); err != nil {
+		t.Fatal(err)
+	}
+	s.Start()
+	for i := 0; i < 4; i++ {
+		s.RenderNextFrame()
+	}
+	s.Stop()
+
+	out := buf.String()
+	for _, want := range []string{"\x1b[31ma", "\x1b[34mb", "\x1b[31mc", "\x1b[34ma"} {
+		i := strings.Index(out, want)
+		if i < 0 {
+			t.Fatalf("output %q doesn't contain %q next", buf.String(), want)
+		}
+		out = out[i+len(want):]
+	}
+}
+
+func TestTextAttributes(t *testing.T) {
+	withColor(t)
+	s, _ := newTestSpinner(t, Options{Managed: true, Text: "loud", TextAttributes: []string{"bold", "underline"}})
+	s.Start()
+	s.RenderNextFrame()
+	defer s.Stop()
+
+	s.mu.RLock()
+	out := s.lastWritten
+	s.mu.RUnlock()
+	if want := "\x1b[1;4mloud\x1b[0m"; !strings.Contains(out, want) {
+		t.Errorf("frame %q doesn't contain %q", out, want)
+	}
+}
+
+func TestFrameEndsWithReset(t *testing.T) {
+	withColor(t)
+	s, buf := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"a", "b"}, Text: "styled"})
+	s.Start()
+	s.RenderNextFrame()
+	s.RenderNextFrame()
+	s.Stop()
+
+	if n := strings.Count(buf.String(), " styled"+resetSequence); n != 2 {
+		t.Errorf("%d of 2 frames end with a reset: %q", n, buf.String())
+	}
+
+	s, buf = newTestSpinner(t, Options{Managed: true, DisableColor: true, Text: "plain"})
+	s.Start()
+	s.RenderNextFrame()
+	s.Stop()
+	if strings.Contains(buf.String(), resetSequence) {
+		t.Errorf("uncolored frame ends with a reset: %q", buf.String())
+	}
+}
+
+func TestConditionalColor(t *testing.T) {
+	withColor(t)
+	s, _ := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"a", "b"}})
+	healthy := true
+	if err := s.SetConditionalColor(func() bool { return healthy }, "green", "red"); err != nil {
+		t.Fatal(err)
+	}
+	s.Start()
+	defer s.Stop()
+
+	s.RenderNextFrame()
+	if got := lastWritten(s); !strings.Contains(got, "\x1b[32ma") {
+		t.Errorf("frame %q isn't green while the condition holds", got)
+	}
+	healthy = false
+	s.RenderNextFrame()
+	if got := lastWritten(s); !strings.Contains(got, "\x1b[31mb") {
+		t.Errorf("frame %q isn't red once the condition fails", got)
+	}
+}
+
+// lastWritten returns the last frame written out, with its styling.
+func lastWritten(s *Spinner) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastWritten
+}
+
+func TestNoColor(t *testing.T) {
+	withColor(t)
+	setenv(t, "NO_COLOR", "1")
+	buf := &syncBuffer{}
+	s := New(Options{Writer: buf, ForceTerminal: true, Managed: true, Symbol: ">", PrefixText: "job", Text: "working", TextAttributes: []string{"bold"}})
+	s.ShowElaspedSeconds = false
+	if err := s.SetSymbolColor("red"); err != nil {
+		t.Fatal(err)
+	}
+	s.Start()
+	s.RenderNextFrame()
+	s.RenderNextFrame()
+	s.Succeed("done")
+
+	// only the erase needed to redraw is left
+	if out := strings.ReplaceAll(buf.String(), "\x1b[K", ""); strings.Contains(out, "\x1b[") {
+		t.Errorf("output %q holds escape sequences", buf.String())
+	}
+	if !strings.Contains(buf.String(), "done") {
+		t.Errorf("output %q doesn't hold the success message", buf.String())
+	}
+}
+
+func TestSetColors(t *testing.T) {
+	withColor(t)
+	s, buf := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"a", "b"}})
+	if err := s.SetColors("red", "green", "blue"); err != nil {
+		t.Fatal(err)
+	}
+	s.Start()
+	for i := 0; i < 4; i++ {
+		s.RenderNextFrame()
+	}
+	s.Stop()
+
+	out := buf.String()
+	for _, want := range []string{"\x1b[31ma", "\x1b[32mb", "\x1b[34ma", "\x1b[31mb"} {
+		i := strings.Index(out, want)
+		if i < 0 {
+			t.Fatalf("output %q doesn't contain %q next", buf.String(), want)
+		}
+		out = out[i+len(want):]
+	}
+
+	if err := s.SetColors("red", "mauve", "puce"); err == nil || !strings.Contains(err.Error(), `"mauve"`) {
+		t.Errorf("SetColors with invalid colors = %v, want an error naming mauve", err)
+	}
+}
+
+func TestSegmentColors(t *testing.T) {
+	withColor(t)
+	s, _ := newTestSpinner(t, Options{
+		Managed:      true,
+		CharacterSet: []string{"-"},
+		Color:        "cyan",
+		Symbol:       ">",
+		SymbolColor:  "red",
+		PrefixText:   "job",
+		PrefixColor:  "faint",
+		Text:         "working",
+		TextColor:    "green",
+	})
+	s.Start()
+	s.RenderNextFrame()
+	s.mu.RLock()
+	out := s.lastWritten
+	s.mu.RUnlock()
+	s.Stop()
+
+	frame := out
+	for _, want := range []string{"\x1b[31m>\x1b[0m", "\x1b[2mjob\x1b[0m", "\x1b[36m-\x1b[0m", "\x1b[32mworking\x1b[0m"} {
+		i := strings.Index(out, want)
+		if i < 0 {
+			t.Fatalf("frame %q doesn't contain %q next", frame, want)
+		}
+		out = out[i+len(want):]
+	}
+
+	// without colors of their own the segments are plain
+	s, _ = newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"-"}, Symbol: ">", PrefixText: "job", Text: "working"})
+	s.Start()
+	s.RenderNextFrame()
+	s.mu.RLock()
+	out = s.lastWritten
+	s.mu.RUnlock()
+	s.Stop()
+	if !strings.HasPrefix(out, "\r> job ") || !strings.Contains(out, " working") {
+		t.Errorf("frame %q has colored segments", out)
+	}
+}
+
+func TestStopAndPersistWithColor(t *testing.T) {
+	withColor(t)
+	s, buf := newTestSpinner(t, Options{Managed: true})
+	s.Start()
+	if err := s.StopAndPersistWithColor("!", "careful", "yellow"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "\x1b[33m!\x1b[0m careful"; !strings.Contains(buf.String(), want) {
+		t.Errorf("output %q doesn't contain %q", buf.String(), want)
+	}
+
+	s.Start()
+	buf.Reset()
+	if err := s.StopAndPersistWithColor("!", "careful", "mauve"); err == nil {
+		t.Error("invalid color: no error")
+	}
+	if !s.Active() || buf.String() != "" {
+		t.Errorf("invalid color stopped the spinner, wrote %q", buf.String())
+	}
+	s.Stop()
+}
+
+func TestStyledFramesCached(t *testing.T) {
+	withColor(t)
+	s, buf := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"a", "b"}})
+	calls := 0
+	style := s.color
+	s.color = func(a ...interface{}) string {
+		calls++
+		return style(a...)
+	}
+	s.Start()
+	for i := 0; i < 6; i++ {
+		s.RenderNextFrame()
+	}
+	if calls != 2 {
+		t.Errorf("%d frames styled for 6 frames of 2 characters, want 2", calls)
+	}
+
+	if err := s.Color("green"); err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	s.RenderNextFrame()
+	s.Stop()
+	if !strings.Contains(buf.String(), "\x1b[32ma") {
+		t.Errorf("frame %q isn't styled anew after the color changed", buf.String())
+	}
+}
+
+func TestEmptyNoColor(t *testing.T) {
+	cleanEnv(t)
+	setenv(t, "NO_COLOR", "")
+	buf := &syncBuffer{}
+	s := New(Options{Writer: buf, ForceTerminal: true, Managed: true, CharacterSet: []string{"-"}})
+	s.Start()
+	s.Stop()
+	// the styling left to the spinner stays on, such as hiding the cursor
+	if !strings.HasPrefix(buf.String(), "\x1b[?25l") {
+		t.Errorf("output %q doesn't hide the cursor with NO_COLOR empty", buf.String())
+	}
+}
+
+func BenchmarkRenderNextFrame(b *testing.B) {
+	for _, bench := range []struct {
+		name  string
+		style func(s *Spinner) error
+	}{
+		// the styled characters are cached
+		{"Color", func(s *Spinner) error { return s.Color("green") }},
+		// the characters are styled on every frame
+		{"TwoTone", func(s *Spinner) error { return s.TwoTone("red", "blue") }},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			withColor(b)
+			s := New(Options{Writer: ioutil.Discard, ForceTerminal: true, Managed: true, CharacterSet: CharSets["dots"]})
+			s.ShowElaspedSeconds = false
+			s.MinInterval = 0
+			if err := bench.style(s); err != nil {
+				b.Fatal(err)
+			}
+			s.Start()
+			defer s.Stop()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				s.RenderNextFrame()
+			}
+		})
+	}
+}
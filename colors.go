@@ -143,3 +143,19 @@ var colorAttributeMap = map[string]color.Attribute{
 func validColor(c string) bool {
 	return validColors[c]
 }
+
+// colorFunc returns a function styling its arguments with the given colors and
+// attributes.
+func colorFunc(colors ...string) (func(a ...interface{}) string, error) {
+	colorAttributes := make([]color.Attribute, len(colors))
+
+	// Verify colours are valid and place the appropriate attribute in the array
+	for index, c := range colors {
+		if !validColor(c) {
+			return nil, errInvalidColor
+		}
+		colorAttributes[index] = colorAttributeMap[c]
+	}
+
+	return color.New(colorAttributes...).SprintFunc(), nil
+}
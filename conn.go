@@ -0,0 +1,54 @@
+package spintron
+
+import (
+	"net"
+	"time"
+)
+
+// connWriteTimeout is how long writing a frame to a net.Conn may take before
+// the spinner gives up on the connection.
+const connWriteTimeout = 2 * time.Second
+
+// setWriteDeadline bounds the next write when the writer is a net.Conn, so a
+// dropped connection can't block the spinner. It's set by out, for every
+// write.
+// Caller must already hold s.lock.
+func (s *Spinner) setWriteDeadline() {
+	if conn, ok := s.Writer.(net.Conn); ok {
+		conn.SetWriteDeadline(time.Now().Add(connWriteTimeout))
+	}
+}
+
+// writeFailed records a failed write. When the writer is a net.Conn the
+// spinner stops itself, without writing anything more to the connection.
+// Caller must already hold s.lock.
+func (s *Spinner) writeFailed(err error) {
+	s.debugf("write error: %v", err)
+	s.err = err
+	if _, ok := s.Writer.(net.Conn); ok && s.active {
+		s.debugf("stopping after write error on connection")
+		s.active = false
+		stopSignals(s.sigChan)
+		s.releaseWriter()
+	}
+}
+
+// startFailed reports whether the first write of Start failed on a net.Conn,
+// recording the error, in which case the spinner isn't started on it.
+// Caller must already hold s.lock.
+func (s *Spinner) startFailed(err error) bool {
+	if _, ok := s.Writer.(net.Conn); !ok || err == nil {
+		return false
+	}
+	s.debugf("start: write error on connection: %v", err)
+	s.err = err
+	return true
+}
+
+// Err returns the error of the last failed write, if any, since the spinner
+// was started.
+func (s *Spinner) Err() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.err
+}
@@ -0,0 +1,83 @@
+package spintron
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+// timeoutErr reports whether err is a timed out write.
+func timeoutErr(err error) bool {
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
+
+// within runs fn and fails the test if it's still running after d.
+func within(t *testing.T, d time.Duration, what string, fn func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		fn()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(d):
+		t.Fatalf("%s didn't time out", what)
+	}
+}
+
+func TestConnSelfStops(t *testing.T) {
+	cleanEnv(t)
+	// nobody reads from the other end, so writes block until the deadline
+	conn, other := net.Pipe()
+	defer conn.Close()
+	defer other.Close()
+
+	s := New(Options{Writer: conn, ForceTerminal: true, Managed: true})
+	within(t, connWriteTimeout+time.Second, "hiding the cursor on a blocked connection", s.Start)
+	if s.Active() {
+		t.Error("spinner started on a connection it couldn't write to")
+	}
+	if !timeoutErr(s.Err()) {
+		t.Errorf("error %v, want a timeout", s.Err())
+	}
+}
+
+func TestConnSelfStopsOnFrame(t *testing.T) {
+	cleanEnv(t)
+	conn, other := net.Pipe()
+	defer conn.Close()
+	defer other.Close()
+
+	s := New(Options{Writer: conn, ForceTerminal: true, Managed: true})
+	// the cursor is hidden while the other end reads, then it stops
+	// reading
+	read := make(chan struct{})
+	go func() {
+		b := make([]byte, 64)
+		other.Read(b)
+		close(read)
+	}()
+	s.Start()
+	<-read
+	within(t, connWriteTimeout+time.Second, "a frame on a blocked connection", s.RenderNextFrame)
+
+	if s.Active() {
+		t.Error("spinner still runs after the connection timed out")
+	}
+	if !timeoutErr(s.Err()) {
+		t.Errorf("error %v, want a timeout", s.Err())
+	}
+}
+
+func TestConnSaveCursor(t *testing.T) {
+	cleanEnv(t)
+	conn, other := net.Pipe()
+	defer conn.Close()
+	defer other.Close()
+
+	s := New(Options{Writer: conn, ForceTerminal: true, Managed: true})
+	// not only the frames are bounded by the deadline
+	within(t, connWriteTimeout+time.Second, "saving the cursor on a blocked connection", s.SaveCursor)
+}
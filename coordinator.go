@@ -0,0 +1,85 @@
+package spintron
+
+import (
+	"io"
+	"reflect"
+	"sync"
+)
+
+// Coordinator hands out exclusive use of a writer, so independent spinners
+// sharing it take turns instead of drawing over each other.
+type Coordinator struct {
+	sem chan struct{}
+}
+
+var (
+	coordinatorsMu sync.Mutex
+	coordinators   = map[io.Writer]*Coordinator{}
+)
+
+// Coordinate returns the coordinator for the given writer. Every call with the
+// same writer returns the same coordinator.
+func Coordinate(w io.Writer) *Coordinator {
+	if w == nil || !reflect.TypeOf(w).Comparable() {
+		// can't be used as a map key, so it can't be shared either
+		return newCoordinator()
+	}
+
+	coordinatorsMu.Lock()
+	defer coordinatorsMu.Unlock()
+	c, ok := coordinators[w]
+	if !ok {
+		c = newCoordinator()
+		coordinators[w] = c
+	}
+	return c
+}
+
+// newCoordinator returns a coordinator that isn't held by anyone.
+func newCoordinator() *Coordinator {
+	return &Coordinator{sem: make(chan struct{}, 1)}
+}
+
+// Acquire blocks until the writer is free and then takes it.
+func (c *Coordinator) Acquire() {
+	c.sem <- struct{}{}
+}
+
+// Release frees the writer for the next one waiting in Acquire.
+func (c *Coordinator) Release() {
+	select {
+	case <-c.sem:
+	default:
+	}
+}
+
+// acquireWriter waits for exclusive use of the writer when the spinner is
+// coordinated. It reports false if the spinner was started in the meantime.
+func (s *Spinner) acquireWriter() bool {
+	s.mu.RLock()
+	coordinated, active, w := s.Coordinated, s.active, s.Writer
+	s.mu.RUnlock()
+	if !coordinated || active {
+		return true
+	}
+
+	c := Coordinate(w)
+	c.Acquire()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.active || s.coordinator != nil {
+		c.Release()
+		return false
+	}
+	s.coordinator = c
+	return true
+}
+
+// releaseWriter gives up exclusive use of the writer, if the spinner has it.
+// Caller must already hold s.lock.
+func (s *Spinner) releaseWriter() {
+	if s.coordinator != nil {
+		s.coordinator.Release()
+		s.coordinator = nil
+	}
+}
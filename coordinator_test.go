@@ -0,0 +1,49 @@
+package spintron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCoordinatedSpinnersTakeTurns(t *testing.T) {
+	buf := &syncBuffer{}
+	first := New(Options{Writer: buf, ForceTerminal: true, Managed: true, Coordinated: true})
+	second := New(Options{Writer: buf, ForceTerminal: true, Managed: true, Coordinated: true})
+
+	first.Start()
+	started := make(chan struct{})
+	go func() {
+		second.Start()
+		close(started)
+	}()
+
+	select {
+	case <-started:
+		t.Fatal("second spinner started while the first one holds the writer")
+	case <-time.After(20 * time.Millisecond):
+	}
+	if second.Active() {
+		t.Error("second spinner is active while it waits for the writer")
+	}
+
+	first.Stop()
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("second spinner didn't start once the first one released the writer")
+	}
+	if !second.Active() {
+		t.Error("second spinner isn't active after taking the writer")
+	}
+	second.Stop()
+}
+
+func TestCoordinate(t *testing.T) {
+	buf := &syncBuffer{}
+	if Coordinate(buf) != Coordinate(buf) {
+		t.Error("the same writer got different coordinators")
+	}
+	if Coordinate(buf) == Coordinate(&syncBuffer{}) {
+		t.Error("different writers share a coordinator")
+	}
+}
@@ -0,0 +1,66 @@
+package spintron
+
+import "sync"
+
+var (
+	defaultSpinner *Spinner
+	defaultOnce    sync.Once
+)
+
+// Default returns the spinner used by the package-level functions. It is
+// created with the default options the first time it's needed, and is safe for
+// concurrent use. It's independent of the spinners created with New, so the
+// package-level functions can be mixed with those freely.
+func Default() *Spinner {
+	defaultOnce.Do(func() {
+		defaultSpinner = New(Options{})
+	})
+	return defaultSpinner
+}
+
+// Start starts the default spinner with the given text.
+func Start(text string) {
+	s := Default()
+	s.SetText(text)
+	s.Start()
+}
+
+// Stop stops the default spinner.
+func Stop() {
+	Default().Stop()
+}
+
+// Succeed stops the default spinner and prints out a success message.
+func Succeed(text string) {
+	Default().Succeed(text)
+}
+
+// Fail stops the default spinner and prints out a failure message.
+func Fail(text string) {
+	Default().Fail(text)
+}
+
+// Warn stops the default spinner and prints out a warning message.
+func Warn(text string) {
+	Default().Warn(text)
+}
+
+// Info stops the default spinner and prints out an info message.
+func Info(text string) {
+	Default().Info(text)
+}
+
+// StopAndPersist stops the default spinner and prints out a symbol with text.
+func StopAndPersist(symbol string, text string) {
+	Default().StopAndPersist(symbol, text)
+}
+
+// SetText sets the text shown by the default spinner.
+func SetText(text string) {
+	Default().SetText(text)
+}
+
+// UpdateText will change the text shown by the default spinner.
+func UpdateText(text string) {
+	Default().SetText(text)
+}
@@ -0,0 +1,72 @@
+package spintron
+
+import (
+	"strings"
+	"testing"
+)
+
+// useDefault points the default spinner at a buffer for the duration of the
+// test.
+func useDefault(t *testing.T) *syncBuffer {
+	t.Helper()
+	buf := &syncBuffer{}
+	s := Default()
+	s.mu.Lock()
+	writer, force := s.Writer, s.ForceTerminal
+	s.Writer, s.ForceTerminal = buf, true
+	s.ShowElaspedSeconds = false
+	s.mu.Unlock()
+	t.Cleanup(func() {
+		Stop()
+		s.mu.Lock()
+		s.Writer, s.ForceTerminal = writer, force
+		s.mu.Unlock()
+	})
+	return buf
+}
+
+func TestDefault(t *testing.T) {
+	buf := useDefault(t)
+	if Default() != Default() {
+		t.Fatal("Default returns a new spinner on every call")
+	}
+
+	Start("working")
+	if !Default().Active() {
+		t.Fatal("Start didn't start the default spinner")
+	}
+	SetText("still working")
+	if got := text(Default()); got != "still working" {
+		t.Errorf("text %q, want %q", got, "still working")
+	}
+	Succeed("done")
+	if Default().Active() {
+		t.Error("Succeed didn't stop the default spinner")
+	}
+	if !strings.Contains(buf.String(), " done") {
+		t.Errorf("output %q doesn't contain the success message", buf.String())
+	}
+}
+
+func TestDefaultPersist(t *testing.T) {
+	buf := useDefault(t)
+	for _, tt := range []struct {
+		persist func()
+		want    string
+	}{
+		{func() { Warn("careful") }, " careful"},
+		{func() { Info("note") }, " note"},
+		{func() { StopAndPersist(">", "kept") }, "> kept"},
+	} {
+		buf.Reset()
+		Start("working")
+		UpdateText("still working")
+		tt.persist()
+		if Default().Active() {
+			t.Error("default spinner still active")
+		}
+		if got := resultLine(buf.String()); !strings.HasSuffix(got, tt.want) {
+			t.Errorf("result line %q, want it to end with %q", got, tt.want)
+		}
+	}
+}
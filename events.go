@@ -0,0 +1,163 @@
+package spintron
+
+import "time"
+
+// eventBuffer is the number of events buffered for a slow reader of Events.
+const eventBuffer = 64
+
+// EventType identifies what happened in the lifecycle of a spinner.
+type EventType int
+
+const (
+	// EventStarted is published when the spinner starts.
+	EventStarted EventType = iota
+	// EventFrameRendered is published when a frame is written out.
+	EventFrameRendered
+	// EventTextChanged is published when the text shown changes.
+	EventTextChanged
+	// EventStopped is published when the spinner stops.
+	EventStopped
+	// EventPersisted is published when a status line is printed.
+	EventPersisted
+)
+
+// String returns the name of the event type.
+func (t EventType) String() string {
+	switch t {
+	case EventStarted:
+		return "started"
+	case EventFrameRendered:
+		return "frame rendered"
+	case EventTextChanged:
+		return "text changed"
+	case EventStopped:
+		return "stopped"
+	case EventPersisted:
+		return "persisted"
+	}
+	return "unknown"
+}
+
+// OverflowPolicy decides what happens to an event when the Events channel is
+// full.
+type OverflowPolicy int
+
+const (
+	// Drop drops the new event, so a slow reader never holds up the spinner.
+	Drop OverflowPolicy = iota
+	// Block keeps every event: the spinner waits for the reader to make
+	// room, rendering no frames meanwhile.
+	Block
+	// DropOldest drops the oldest event buffered to make room for the new one.
+	DropOldest
+)
+
+// Event describes something that happened to a spinner.
+type Event struct {
+	Type  EventType
+	Time  time.Time
+	Frame int    // Frame is the index of the character rendered, for EventFrameRendered
+	Text  string // Text is the new text for EventTextChanged and the printed text for EventPersisted
+}
+
+// Events returns a channel publishing the lifecycle events of the spinner.
+// What happens when the channel is full is decided by EventOverflow, by
+// default the new events are dropped. The channel is never closed.
+func (s *Spinner) Events() <-chan Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.events == nil {
+		s.events = make(chan Event, eventBuffer)
+		s.eventText = s.Text
+	}
+	return s.events
+}
+
+// emit publishes an event of the given type, if anyone asked for events.
+// Caller must already hold s.lock.
+func (s *Spinner) emit(t EventType, frame int, text string) {
+	if s.events == nil {
+		return
+	}
+	e := Event{Type: t, Time: time.Now(), Frame: frame, Text: text}
+	switch s.EventOverflow {
+	case Block:
+		// queued rather than sent here, as a reader calling back into the
+		// spinner would wait on the lock held while the send waits on it;
+		// the queue is kept short by waitForEvents
+		s.eventQueue = append(s.eventQueue, e)
+		if !s.eventSending {
+			s.eventSending = true
+			go s.sendEvents(s.events)
+		}
+	case DropOldest:
+		for {
+			select {
+			case s.events <- e:
+				return
+			default:
+			}
+			select {
+			case <-s.events:
+			default:
+			}
+		}
+	default:
+		select {
+		case s.events <- e:
+		default:
+		}
+	}
+}
+
+// sendEvents sends the queued events to events in order, without holding the
+// lock while waiting for the reader. It returns once the queue is empty.
+func (s *Spinner) sendEvents(events chan<- Event) {
+	for {
+		s.mu.Lock()
+		if len(s.eventQueue) == 0 {
+			s.eventQueue = nil
+			s.eventSending = false
+			s.mu.Unlock()
+			return
+		}
+		e := s.eventQueue[0]
+		s.eventQueue = s.eventQueue[1:]
+		if s.eventRoom != nil {
+			close(s.eventRoom)
+			s.eventRoom = nil
+		}
+		s.mu.Unlock()
+		events <- e
+	}
+}
+
+// waitForEvents waits, under the Block policy, while eventBuffer events are
+// queued on top of those in the Events channel already, so a slow reader holds
+// up the spinner instead of the queue growing without bounds. It's called
+// before taking the lock for anything publishing events, so a reader calling
+// back into the spinner doesn't wait on the spinner waiting on the reader.
+func (s *Spinner) waitForEvents() {
+	s.mu.Lock()
+	for s.EventOverflow == Block && len(s.eventQueue) >= eventBuffer {
+		if s.eventRoom == nil {
+			s.eventRoom = make(chan struct{})
+		}
+		room := s.eventRoom
+		s.mu.Unlock()
+		<-room
+		s.mu.Lock()
+	}
+	s.mu.Unlock()
+}
+
+// emitTextChange publishes an EventTextChanged if the text changed since the
+// last one.
+// Caller must already hold s.lock.
+func (s *Spinner) emitTextChange() {
+	if s.events == nil || s.Text == s.eventText {
+		return
+	}
+	s.eventText = s.Text
+	s.emit(EventTextChanged, s.frame, s.Text)
+}
@@ -0,0 +1,184 @@
+package spintron
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEvents(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Managed: true})
+	events := s.Events()
+	s.Start()
+	s.RenderNextFrame()
+	s.SetText("working")
+	s.RenderNextFrame()
+	s.Stop()
+
+	var got []EventType
+	for len(events) > 0 {
+		e := <-events
+		got = append(got, e.Type)
+		if e.Type == EventTextChanged && e.Text != "working" {
+			t.Errorf("text changed to %q, want %q", e.Text, "working")
+		}
+	}
+	want := []EventType{EventStarted, EventFrameRendered, EventTextChanged, EventFrameRendered, EventStopped}
+	if len(got) != len(want) {
+		t.Fatalf("events %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("events %v, want %v", got, want)
+		}
+	}
+}
+
+func TestEventsDropOldest(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Managed: true, EventOverflow: DropOldest})
+	events := s.Events()
+	s.Start()
+	for i := 0; i < 2*eventBuffer; i++ {
+		s.RenderNextFrame()
+	}
+	s.Stop()
+
+	if len(events) != eventBuffer {
+		t.Fatalf("%d events buffered, want %d", len(events), eventBuffer)
+	}
+	if e := <-events; e.Type == EventStarted {
+		t.Error("oldest event wasn't dropped")
+	}
+	var last Event
+	for len(events) > 0 {
+		last = <-events
+	}
+	if last.Type != EventStopped {
+		t.Errorf("last event %v, want %v", last.Type, EventStopped)
+	}
+}
+
+func TestEventsBlockReaderCallsBack(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Managed: true, EventOverflow: Block})
+	events := s.Events()
+
+	var rendered int32
+	done := make(chan struct{})
+	go func() {
+		s.Start()
+		for i := 0; i < 4*eventBuffer; i++ {
+			s.RenderNextFrame()
+			atomic.AddInt32(&rendered, 1)
+		}
+		s.Stop()
+		close(done)
+	}()
+
+	// nothing is read, so the spinner has to wait once the channel and the
+	// queue are full
+	last := int32(-1)
+	for n := atomic.LoadInt32(&rendered); n != last; n = atomic.LoadInt32(&rendered) {
+		last = n
+		select {
+		case <-done:
+			t.Fatal("spinner didn't wait for a stalled reader")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	s.mu.RLock()
+	queued := len(s.eventQueue)
+	s.mu.RUnlock()
+	if queued > eventBuffer {
+		t.Errorf("%d events queued for a stalled reader, want at most %d", queued, eventBuffer)
+	}
+
+	got := make(chan []EventType)
+	go func() {
+		var types []EventType
+		for e := range events {
+			types = append(types, e.Type)
+			// reading the spinner back must not wait on the spinner
+			// waiting for room
+			s.Active()
+			if e.Type == EventStopped {
+				break
+			}
+		}
+		got <- types
+	}()
+
+	timeout := time.After(5 * time.Second)
+	select {
+	case <-done:
+	case <-timeout:
+		t.Fatal("spinner deadlocked with a reader calling back into it")
+	}
+	select {
+	case types := <-got:
+		if want := 4*eventBuffer + 2; len(types) != want {
+			t.Fatalf("reader got %d events, want %d", len(types), want)
+		}
+		for i, typ := range types {
+			want := EventFrameRendered
+			switch i {
+			case 0:
+				want = EventStarted
+			case len(types) - 1:
+				want = EventStopped
+			}
+			if typ != want {
+				t.Errorf("event %d is %v, want %v", i, typ, want)
+			}
+		}
+	case <-timeout:
+		t.Fatal("reader didn't get EventStopped")
+	}
+
+	// the goroutine sending the queue returns once it's sent everything
+	waitFor(t, "the events to be sent", func() bool {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		return !s.eventSending
+	})
+}
+
+func TestEventsPersisted(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Managed: true})
+	events := s.Events()
+	s.Start()
+	s.PersistLine("*", "step one")
+	s.Succeed("all done")
+
+	var got []string
+	for len(events) > 0 {
+		e := <-events
+		if e.Type == EventPersisted {
+			got = append(got, e.Type.String()+": "+e.Text)
+		} else {
+			got = append(got, e.Type.String())
+		}
+	}
+	want := "started, persisted: step one, frame rendered, stopped, persisted: all done"
+	if strings.Join(got, ", ") != want {
+		t.Errorf("events %q, want %q", strings.Join(got, ", "), want)
+	}
+}
+
+func TestEventsDrop(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Managed: true})
+	events := s.Events()
+	s.Start()
+	for i := 0; i < 2*eventBuffer; i++ {
+		s.RenderNextFrame()
+	}
+	s.Stop()
+
+	// the newest events are dropped, the oldest are kept
+	if len(events) != eventBuffer {
+		t.Fatalf("%d events buffered, want %d", len(events), eventBuffer)
+	}
+	if e := <-events; e.Type != EventStarted {
+		t.Errorf("first event %v, want %v", e.Type, EventStarted)
+	}
+}
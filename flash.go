@@ -0,0 +1,63 @@
+package spintron
+
+import "time"
+
+// FlashMessage shows the given message in place of the text for the given
+// duration while the spinner keeps spinning, then restores the text. When
+// flashes overlap the latest one wins and the original text is restored once
+// it expires. Text set through SetText meanwhile is the one restored.
+func (s *Spinner) FlashMessage(msg string, d time.Duration) {
+	s.mu.Lock()
+	if !s.flashing {
+		s.flashText = s.Text
+		s.flashing = true
+	}
+	s.flashID++
+	id := s.flashID
+	s.Text = msg
+	s.reportProgress()
+	s.mu.Unlock()
+
+	time.AfterFunc(d, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.flashID != id {
+			// a later flash took over
+			return
+		}
+		s.Text = s.flashText
+		s.flashing = false
+		s.reportProgress()
+	})
+}
+
+// ShowStatic shows the given character in place of the animated one for the
+// given duration, e.g. a pause icon while waiting for input, along with the
+// symbol, prefix text and text as usual. The animation stands still meanwhile
+// and carries on from the same frame afterwards. When calls overlap the latest
+// one wins.
+func (s *Spinner) ShowStatic(char string, d time.Duration) {
+	s.mu.Lock()
+	s.staticChar = char
+	s.staticShown = true
+	s.staticID++
+	id := s.staticID
+	if s.active && !s.static() {
+		if !isWindowsTerminalOnWindows {
+			s.erase()
+		}
+		s.draw()
+	}
+	s.mu.Unlock()
+
+	time.AfterFunc(d, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.staticID != id {
+			// a later call took over
+			return
+		}
+		s.staticShown = false
+		s.staticChar = ""
+	})
+}
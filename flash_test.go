@@ -0,0 +1,94 @@
+package spintron
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// lastFrame returns the last frame drawn, without styling.
+func lastFrame(s *Spinner) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return strings.TrimPrefix(s.lastOutput, "\r")
+}
+
+// text returns the text the spinner shows.
+func text(s *Spinner) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Text
+}
+
+// waitFor polls cond until it holds, failing the test after a second.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestFlashMessage(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Managed: true, Text: "working"})
+	s.FlashMessage("saved", 20*time.Millisecond)
+	if got := text(s); got != "saved" {
+		t.Errorf("text %q while flashing, want %q", got, "saved")
+	}
+	waitFor(t, "the text to be restored", func() bool { return text(s) == "working" })
+}
+
+func TestFlashMessageOverlapping(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Managed: true, Text: "working"})
+	s.FlashMessage("first", 10*time.Millisecond)
+	s.FlashMessage("second", 40*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	if got := text(s); got != "second" {
+		t.Errorf("text %q after the first flash expired, want %q", got, "second")
+	}
+	waitFor(t, "the text to be restored", func() bool { return text(s) == "working" })
+}
+
+func TestFlashMessageSetText(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Managed: true, Text: "working"})
+	s.FlashMessage("saved", 20*time.Millisecond)
+	s.SetText("uploading")
+	if got := text(s); got != "saved" {
+		t.Errorf("text %q while flashing, want %q", got, "saved")
+	}
+	waitFor(t, "the new text to be shown", func() bool { return text(s) == "uploading" })
+	time.Sleep(30 * time.Millisecond)
+	if got := text(s); got != "uploading" {
+		t.Errorf("text %q after the flash, want %q", got, "uploading")
+	}
+}
+
+func TestShowStatic(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"a", "b"}})
+	s.Start()
+	s.RenderNextFrame()
+	s.ShowStatic("=", 20*time.Millisecond)
+	s.RenderNextFrame()
+	if shown := lastFrame(s); shown != "=" {
+		t.Errorf("frame %q while showing a static character, want %q", shown, "=")
+	}
+	waitFor(t, "the animation to carry on", func() bool {
+		s.RenderNextFrame()
+		return lastFrame(s) == "b"
+	})
+	s.Stop()
+}
+
+func TestShowStaticLayout(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"a"}, Symbol: ">", PrefixText: "job", Text: "waiting"})
+	s.Start()
+	s.ShowStatic("=", time.Hour)
+	s.RenderNextFrame()
+	defer s.Stop()
+	if got, want := lastFrame(s), "> job = waiting"; got != want {
+		t.Errorf("frame %q, want the static character laid out like the others %q", got, want)
+	}
+}
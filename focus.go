@@ -0,0 +1,180 @@
+package spintron
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+// dimColor styles the spinner while the terminal doesn't have focus. The color
+// is made on every call, like the others are when they're set, so it follows
+// NO_COLOR as it is then rather than when the package was loaded.
+func dimColor(a ...interface{}) string {
+	return color.New(color.Faint).Sprint(a...)
+}
+
+// focusInput reads FocusReader on behalf of both the spinner and the
+// application: the focus reports are taken out and the rest is kept for the
+// application to read through Input. Only one read of FocusReader is in flight
+// at a time, so nothing read is lost to the other side.
+type focusInput struct {
+	s        *Spinner
+	r        io.Reader
+	mu       sync.Mutex
+	cond     *sync.Cond
+	buf      []byte // buf holds the input read but not yet taken by the application
+	err      error  // err is the error FocusReader failed with, returned once buf is drained
+	reading  bool   // reading reports whether FocusReader is being read
+	tracking bool   // tracking reports whether the spinner is tracking focus
+	watching bool   // watching reports whether the goroutine watching focus runs
+	pending  []byte // pending holds the start of what may be a focus report
+}
+
+// newFocusInput returns the input of r for the spinner.
+func newFocusInput(s *Spinner, r io.Reader) *focusInput {
+	in := &focusInput{s: s, r: r}
+	in.cond = sync.NewCond(&in.mu)
+	return in
+}
+
+// Read reads the input of the application, without the focus reports. It
+// reads FocusReader itself unless the spinner is reading it already, in
+// which case it waits for what the spinner read.
+func (in *focusInput) Read(p []byte) (int, error) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	for len(in.buf) == 0 {
+		if in.err != nil {
+			return 0, in.err
+		}
+		if in.reading {
+			in.cond.Wait()
+			continue
+		}
+		in.fill()
+	}
+	n := copy(p, in.buf)
+	in.buf = in.buf[n:]
+	if len(in.buf) == 0 {
+		in.buf = nil
+	}
+	return n, nil
+}
+
+// fill reads FocusReader once and keeps what isn't a focus report.
+// Caller must hold in.mu, which is released during the read.
+func (in *focusInput) fill() {
+	in.reading = true
+	in.mu.Unlock()
+	b := make([]byte, 64)
+	n, err := in.r.Read(b)
+	kept := in.filter(b[:n])
+	in.mu.Lock()
+	in.reading = false
+	in.buf = append(in.buf, kept...)
+	if err != nil {
+		in.err = err
+	}
+	in.cond.Broadcast()
+}
+
+// filter takes the focus in (ESC [ I) and focus out (ESC [ O) reports out of
+// b, dimming the spinner while the terminal doesn't have focus, and returns
+// the rest. The start of a report is held back until it's complete.
+// Only the reader of FocusReader calls it, without holding in.mu.
+func (in *focusInput) filter(b []byte) []byte {
+	var kept []byte
+	for _, c := range b {
+		switch {
+		case c == '\033':
+			kept = append(kept, in.pending...)
+			in.pending = append(in.pending[:0], c)
+		case len(in.pending) == 1 && c == '[':
+			in.pending = append(in.pending, c)
+		case len(in.pending) == 2 && (c == 'I' || c == 'O'):
+			in.s.mu.Lock()
+			in.s.unfocused = c == 'O' && in.s.active
+			in.s.mu.Unlock()
+			in.pending = in.pending[:0]
+		default:
+			kept = append(kept, in.pending...)
+			kept = append(kept, c)
+			in.pending = in.pending[:0]
+		}
+	}
+	return kept
+}
+
+// watch reads FocusReader while the spinner tracks focus. It returns once the
+// tracking stopped, after the read in flight, if any, completed.
+func (in *focusInput) watch() {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	for in.tracking && in.err == nil {
+		if in.reading {
+			in.cond.Wait()
+			continue
+		}
+		in.fill()
+	}
+	in.watching = false
+}
+
+// Input returns the reader the application should read its input from while
+// TrackFocus is set: the spinner reads FocusReader for the focus reports, and
+// keeps everything else for Input, also what it read last after it stopped.
+// Without TrackFocus it's FocusReader.
+func (s *Spinner) Input() io.Reader {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.TrackFocus {
+		return s.FocusReader
+	}
+	return s.focusInput()
+}
+
+// focusInput returns the input of FocusReader, made when it's first needed.
+// Caller must already hold s.lock.
+func (s *Spinner) focusInput() *focusInput {
+	if s.input == nil || s.input.r != s.FocusReader {
+		s.input = newFocusInput(s, s.FocusReader)
+	}
+	return s.input
+}
+
+// startFocusTracking asks the terminal to report focus changes and starts
+// reading them from FocusReader.
+// Caller must already hold s.lock.
+func (s *Spinner) startFocusTracking() {
+	if !s.TrackFocus {
+		return
+	}
+	fmt.Fprint(s.out(), "\033[?1004h")
+	in := s.focusInput()
+	in.mu.Lock()
+	in.tracking = true
+	if !in.watching {
+		in.watching = true
+		go in.watch()
+	}
+	in.mu.Unlock()
+}
+
+// stopFocusTracking asks the terminal to stop reporting focus changes, and
+// the goroutine reading them to return.
+// Caller must already hold s.lock.
+func (s *Spinner) stopFocusTracking() {
+	if !s.TrackFocus {
+		return
+	}
+	fmt.Fprint(s.out(), "\033[?1004l")
+	s.unfocused = false
+	if in := s.input; in != nil {
+		in.mu.Lock()
+		in.tracking = false
+		in.cond.Broadcast()
+		in.mu.Unlock()
+	}
+}
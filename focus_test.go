@@ -0,0 +1,98 @@
+package spintron
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestTrackFocus(t *testing.T) {
+	withColor(t)
+	r, w := io.Pipe()
+	defer w.Close()
+	s, buf := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"a", "b", "c"}, TrackFocus: true, FocusReader: r})
+	s.Start()
+	if !strings.Contains(buf.String(), "\x1b[?1004h") {
+		t.Errorf("output %q doesn't turn on focus reports", buf.String())
+	}
+
+	unfocused := func(want bool) func() bool {
+		return func() bool {
+			s.mu.RLock()
+			defer s.mu.RUnlock()
+			return s.unfocused == want
+		}
+	}
+
+	w.Write([]byte("\x1b[O"))
+	waitFor(t, "focus out", unfocused(true))
+	s.RenderNextFrame()
+	if got := lastWritten(s); !strings.Contains(got, "\x1b[2ma") {
+		t.Errorf("frame %q isn't dimmed without focus", got)
+	}
+
+	w.Write([]byte("x\x1b[I"))
+	waitFor(t, "focus in", unfocused(false))
+	s.RenderNextFrame()
+	if got := lastWritten(s); !strings.Contains(got, "\x1b[36mb") {
+		t.Errorf("frame %q isn't back to its color with focus", got)
+	}
+
+	s.Stop()
+	if !strings.Contains(buf.String(), "\x1b[?1004l") {
+		t.Errorf("output %q doesn't turn off focus reports", buf.String())
+	}
+}
+
+// readInput reads from r until it has read n bytes.
+func readInput(t *testing.T, r io.Reader, n int) string {
+	t.Helper()
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestFocusInput(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	s, _ := newTestSpinner(t, Options{Managed: true, TrackFocus: true, FocusReader: r})
+	s.Start()
+	in := s.Input()
+
+	// the focus report is taken out, other escape sequences are kept
+	go w.Write([]byte("\x1b[Oa\x1b[Ab"))
+	if got, want := readInput(t, in, 5), "a\x1b[Ab"; got != want {
+		t.Errorf("read %q while running, want %q", got, want)
+	}
+	s.Stop()
+
+	// the read in flight when the spinner stopped ends the goroutine, what
+	// it read still reaches the application
+	go w.Write([]byte("y\n"))
+	if got := readInput(t, in, 2); got != "y\n" {
+		t.Errorf("read %q after Stop, want %q", got, "y\n")
+	}
+	s.mu.RLock()
+	focus := s.input
+	s.mu.RUnlock()
+	waitFor(t, "the focus goroutine to return", func() bool {
+		focus.mu.Lock()
+		defer focus.mu.Unlock()
+		return !focus.watching
+	})
+	go w.Write([]byte("n\n"))
+	if got := readInput(t, in, 2); got != "n\n" {
+		t.Errorf("read %q once the spinner stopped reading, want %q", got, "n\n")
+	}
+}
+
+func TestInputWithoutTrackFocus(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	s, _ := newTestSpinner(t, Options{Managed: true, FocusReader: r})
+	if s.Input() != r {
+		t.Error("Input isn't FocusReader without TrackFocus")
+	}
+}
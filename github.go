@@ -0,0 +1,33 @@
+package spintron
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// isGitHubActions reports whether the process runs in a GitHub Actions workflow.
+func isGitHubActions() bool {
+	return os.Getenv("GITHUB_ACTIONS") == "true"
+}
+
+// startGroup opens a collapsible GitHub Actions log group named after the text
+// instead of animating the spinner.
+// Caller must already hold s.lock.
+func (s *Spinner) startGroup() {
+	if s.active {
+		return
+	}
+	s.active = true
+	s.startTime = time.Now()
+	fmt.Fprintf(s.out(), "::group::%s\n", sanitize(s.Text))
+	s.emit(EventStarted, 0, s.Text)
+}
+
+// endGroup closes the GitHub Actions log group opened by startGroup.
+// Caller must already hold s.lock.
+func (s *Spinner) endGroup() {
+	s.active = false
+	fmt.Fprint(s.out(), "::endgroup::\n")
+	s.emit(EventStopped, 0, "")
+}
@@ -0,0 +1,29 @@
+package spintron
+
+import "testing"
+
+func TestGitHubActionsGroup(t *testing.T) {
+	setenv(t, "GITHUB_ACTIONS", "true")
+	buf := &syncBuffer{}
+	s := New(Options{Writer: buf, Text: "Building", FinalMSG: "built"})
+	s.Start()
+	s.Stop()
+
+	if got, want := buf.String(), "::group::Building\nbuilt\n::endgroup::\n"; got != want {
+		t.Errorf("output %q, want %q", got, want)
+	}
+}
+
+func TestDisableGitHubActions(t *testing.T) {
+	setenv(t, "GITHUB_ACTIONS", "true")
+	buf := &syncBuffer{}
+	s := New(Options{Writer: buf, ForceTerminal: true, Managed: true, CharacterSet: []string{"-"}, DisableGitHubActions: true})
+	s.ShowElaspedSeconds = false
+	s.Start()
+	s.RenderNextFrame()
+	s.Stop()
+
+	if got := frames(buf.String()); len(got) != 1 || got[0] != "-" {
+		t.Errorf("frames %q, want the spinner animated as usual", got)
+	}
+}
@@ -6,6 +6,9 @@ require (
 	github.com/defaltd/log-symbols v0.0.0-20180904225809-59f0737fbf0d
 	github.com/fatih/color v1.13.0
 	github.com/mattn/go-isatty v0.0.14
+	github.com/mattn/go-runewidth v0.0.13
+	golang.org/x/sys v0.0.0-20210630005230-0f9fa26af87c
+	golang.org/x/term v0.0.0-20210220032956-6a3ed077a48d
 )
 
 retract v1.18.2-0.20220411151637-2ebe53d2865d
@@ -0,0 +1,298 @@
+package spintron
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	logSymbols "github.com/defaltd/log-symbols"
+	"github.com/fatih/color"
+)
+
+// Group renders several spinners on consecutive lines, one per spinner, all
+// animated by a single goroutine. The spinners of a group aren't started on
+// their own, the group draws them.
+type Group struct {
+	Writer        io.Writer     // Writer is where the group is drawn, color.Output by default
+	Delay         time.Duration // Delay is the time between frames
+	HideCursor    bool          // HideCursor hides the cursor while the group runs
+	ForceTerminal bool          // ForceTerminal animates the group even if Writer isn't a terminal
+
+	mu       sync.Mutex
+	members  []*member
+	lines    int           // lines is the number of lines drawn last
+	active   bool          // active reports whether the group is running
+	stopChan chan struct{} // stopChan is closed to stop the group, made anew on every start
+}
+
+// childIndent is the number of spaces a sub-task is indented by under its
+// parent.
+const childIndent = 2
+
+// member is a spinner of a group, along with the line it persisted, if any.
+type member struct {
+	spinner   *Spinner
+	persisted string // persisted is the line shown in place of the spinner
+	done      bool   // done reports whether the spinner persisted a line
+	depth     int    // depth is the number of parents the spinner is nested under
+}
+
+// NewGroup provides a pointer to a group of the given spinners.
+func NewGroup(spinners ...*Spinner) *Group {
+	g := &Group{
+		Writer:     color.Output,
+		Delay:      100 * time.Millisecond,
+		HideCursor: true,
+	}
+	for _, s := range spinners {
+		g.Add(s)
+	}
+	return g
+}
+
+// Add appends a spinner to the group, on a line below the others.
+func (g *Group) Add(s *Spinner) {
+	s.waitForEvents()
+	g.waitForEvents()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.members = append(g.members, &member{spinner: s})
+	if g.active {
+		startMember(s)
+	}
+}
+
+// AddChild adds a spinner for a sub-task of parent to the group, on a line
+// below parent and its other sub-tasks, indented further than parent. A
+// parent that isn't in the group is left out, the spinner is added like Add
+// does then.
+func (g *Group) AddChild(parent *Spinner, s *Spinner) {
+	s.waitForEvents()
+	g.waitForEvents()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	at, depth := len(g.members), 0
+	for i, m := range g.members {
+		if m.spinner != parent {
+			continue
+		}
+		parent.mu.Lock()
+		indent := parent.Indent
+		parent.mu.Unlock()
+		s.mu.Lock()
+		s.Indent = indent + childIndent
+		s.mu.Unlock()
+
+		// after the sub-tasks parent has already, nested or not
+		depth = m.depth + 1
+		at = i + 1
+		for at < len(g.members) && g.members[at].depth >= depth {
+			at++
+		}
+		break
+	}
+	g.members = append(g.members, nil)
+	copy(g.members[at+1:], g.members[at:])
+	g.members[at] = &member{spinner: s, depth: depth}
+	if g.active {
+		startMember(s)
+	}
+}
+
+// Remove takes a spinner out of the group along with its line.
+func (g *Group) Remove(s *Spinner) {
+	g.waitForEvents()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i, m := range g.members {
+		if m.spinner == s {
+			g.members = append(g.members[:i], g.members[i+1:]...)
+			break
+		}
+	}
+	if g.active {
+		g.draw()
+	}
+}
+
+// Persist stops animating a spinner of the group and shows the symbol and text
+// on its line instead, while the other spinners keep spinning.
+func (g *Group) Persist(s *Spinner, symbol string, text string) {
+	g.waitForEvents()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, m := range g.members {
+		if m.spinner == s {
+			s.mu.Lock()
+			m.persisted = strings.Trim(s.persistedLine(symbol, text), "\r\n")
+			m.done = true
+			s.emit(EventPersisted, s.frame, text)
+			s.mu.Unlock()
+		}
+	}
+	if g.active {
+		g.draw()
+	}
+}
+
+// Succeed shows a success sign and the text on the line of a spinner of the
+// group.
+func (g *Group) Succeed(s *Spinner, text string) {
+	g.Persist(s, s.statusSymbol("success", logSymbols.SUCCESS), text)
+}
+
+// Fail shows a failure sign and the text on the line of a spinner of the group.
+func (g *Group) Fail(s *Spinner, text string) {
+	g.Persist(s, s.statusSymbol("failure", logSymbols.ERROR), text)
+}
+
+// Start starts animating the spinners of the group.
+func (g *Group) Start() {
+	g.waitForEvents()
+	g.mu.Lock()
+	if g.active || !(g.ForceTerminal || isRunningInTerminal(g.Writer)) {
+		g.mu.Unlock()
+		return
+	}
+	g.active = true
+	g.lines = 0
+	g.stopChan = make(chan struct{})
+	stopChan := g.stopChan
+	for _, m := range g.members {
+		startMember(m.spinner)
+	}
+	if g.HideCursor {
+		fmt.Fprint(g.Writer, "\033[?25l")
+	}
+	delay := g.Delay
+	if delay <= 0 {
+		delay = minDelay
+	}
+	g.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(delay)
+		defer ticker.Stop()
+		for {
+			g.waitForEvents()
+			g.mu.Lock()
+			if !g.active {
+				g.mu.Unlock()
+				return
+			}
+			g.advance()
+			g.draw()
+			g.mu.Unlock()
+
+			select {
+			case <-ticker.C:
+			case <-stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the group, leaving its last lines on screen with the cursor
+// below them.
+func (g *Group) Stop() {
+	g.waitForEvents()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.active {
+		return
+	}
+	g.active = false
+	close(g.stopChan)
+	g.draw()
+	if g.lines > 0 {
+		fmt.Fprint(g.Writer, "\n")
+	}
+	if g.HideCursor {
+		fmt.Fprint(g.Writer, "\033[?25h")
+	}
+	for _, m := range g.members {
+		m.spinner.mu.Lock()
+		m.spinner.emit(EventStopped, m.spinner.frame, "")
+		m.spinner.mu.Unlock()
+	}
+	g.lines = 0
+}
+
+// waitForEvents waits for the readers of the Events of the spinners of the
+// group to make room, see Spinner.waitForEvents.
+func (g *Group) waitForEvents() {
+	g.mu.Lock()
+	members := make([]*Spinner, 0, len(g.members))
+	for _, m := range g.members {
+		members = append(members, m.spinner)
+	}
+	g.mu.Unlock()
+	for _, s := range members {
+		s.waitForEvents()
+	}
+}
+
+// startMember readies a spinner to be drawn by a group.
+func startMember(s *Spinner) {
+	s.mu.Lock()
+	s.startTime = time.Now()
+	s.step = 0
+	s.emit(EventStarted, 0, s.Text)
+	s.mu.Unlock()
+}
+
+// advance moves every spinner still spinning on to its next frame.
+// Caller must already hold g.mu.
+func (g *Group) advance() {
+	for _, m := range g.members {
+		if m.done {
+			continue
+		}
+		s := m.spinner
+		s.mu.Lock()
+		if s.PreUpdate != nil {
+			s.runCallback("PreUpdate", s.PreUpdate)
+		}
+		s.advanceFrame()
+		if s.PostUpdate != nil {
+			s.runCallback("PostUpdate", s.PostUpdate)
+		}
+		s.mu.Unlock()
+	}
+}
+
+// draw redraws the lines of the group in place: it moves the cursor up to the
+// first line drawn last time, clears everything below it and writes the
+// current lines. The cursor is left at the end of the last line.
+// Caller must already hold g.mu.
+func (g *Group) draw() {
+	lines := make([]string, 0, len(g.members))
+	for _, m := range g.members {
+		if m.done {
+			lines = append(lines, m.persisted)
+			continue
+		}
+		s := m.spinner
+		s.mu.Lock()
+		outColor, outPlain := s.compose()
+		s.lastOutput = outPlain
+		s.emit(EventFrameRendered, s.frame, "")
+		s.mu.Unlock()
+		lines = append(lines, strings.TrimPrefix(outColor, "\r"))
+	}
+
+	var b strings.Builder
+	b.WriteString("\r")
+	if g.lines > 1 {
+		// \033[nA moves the cursor up n lines
+		fmt.Fprintf(&b, "\033[%dA", g.lines-1)
+	}
+	// \033[J clears from the cursor to the end of the screen
+	b.WriteString("\033[J")
+	b.WriteString(strings.Join(lines, "\n"))
+	fmt.Fprint(g.Writer, b.String())
+	g.lines = len(lines)
+}
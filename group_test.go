@@ -0,0 +1,109 @@
+package spintron
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// newGroupSpinner returns a spinner for a group, showing the given text after
+// a fixed character.
+func newGroupSpinner(text string) *Spinner {
+	s := New(Options{Text: text, CharacterSet: []string{"-"}})
+	s.ShowElaspedSeconds = false
+	return s
+}
+
+// groupLines returns the lines of the group drawn last, without styling.
+func groupLines(out string) []string {
+	i := strings.LastIndex(out, "\x1b[J")
+	if i < 0 {
+		return nil
+	}
+	out = strings.TrimSuffix(out[i+len("\x1b[J"):], "\n\x1b[?25h")
+	return strings.Split(stripANSI(out), "\n")
+}
+
+func TestGroupAddChild(t *testing.T) {
+	buf := &syncBuffer{}
+	build, deploy := newGroupSpinner("build"), newGroupSpinner("deploy")
+	g := NewGroup(build, deploy)
+	g.Writer = buf
+	g.ForceTerminal = true
+	g.Delay = time.Hour
+	g.Start()
+	compile := newGroupSpinner("compile")
+	g.AddChild(build, compile)
+	g.AddChild(compile, newGroupSpinner("parse"))
+	g.AddChild(build, newGroupSpinner("link"))
+	g.Persist(compile, "*", "compiled")
+	g.Stop()
+
+	want := []string{
+		"- build",
+		"  * compiled",
+		"    - parse",
+		"  - link",
+		"- deploy",
+	}
+	lines := groupLines(buf.String())
+	if len(lines) != len(want) {
+		t.Fatalf("lines %q, want %q", lines, want)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Fatalf("lines %q, want %q", lines, want)
+		}
+	}
+}
+
+func TestGroup(t *testing.T) {
+	buf := &syncBuffer{}
+	build, test := newGroupSpinner("building"), newGroupSpinner("testing")
+	g := NewGroup(build, test)
+	g.Writer = buf
+	g.ForceTerminal = true
+	g.Delay = time.Hour
+	g.Start()
+	g.Succeed(build, "built")
+	g.Stop()
+
+	lines := groupLines(buf.String())
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], " built") || lines[1] != "- testing" {
+		t.Errorf("lines %q, want the persisted build line and the test spinner", lines)
+	}
+	if !strings.HasPrefix(buf.String(), "\x1b[?25l") || !strings.HasSuffix(buf.String(), "\x1b[?25h") {
+		t.Errorf("output %q doesn't hide the cursor and show it again", buf.String())
+	}
+	// each redraw goes back up to the first of the two lines drawn before
+	if !strings.Contains(buf.String(), "\r\x1b[1A\x1b[J") {
+		t.Errorf("output %q doesn't move up to the first line to redraw", buf.String())
+	}
+}
+
+func TestGroupAddRemove(t *testing.T) {
+	buf := &syncBuffer{}
+	build := newGroupSpinner("build")
+	g := NewGroup(build)
+	g.Writer = buf
+	g.ForceTerminal = true
+	g.Delay = time.Hour
+	g.Start()
+	waitFor(t, "the first draw", func() bool { return buf.String() != "" })
+	test, lint := newGroupSpinner("test"), newGroupSpinner("lint")
+	g.Add(test)
+	g.Add(lint)
+	g.Persist(lint, "*", "linted")
+	buf.Reset()
+	g.Remove(test)
+	g.Stop()
+
+	// three lines were drawn, so the redraw moves up two and clears the
+	// line left over by the removed spinner
+	if !strings.HasPrefix(buf.String(), "\r\x1b[2A\x1b[J") {
+		t.Errorf("output %q doesn't move up to the first of three lines", buf.String())
+	}
+	if lines := groupLines(buf.String()); strings.Join(lines, "|") != "- build|* linted" {
+		t.Errorf("lines %q, want build and the persisted lint line", lines)
+	}
+}
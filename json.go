@@ -0,0 +1,45 @@
+package spintron
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// jsonEvent is a line written out for every change of state of a spinner with
+// JSONOutput set.
+type jsonEvent struct {
+	Event  string `json:"event"`            // Event is "start", "text", "persist" or "stop"
+	Status string `json:"status,omitempty"` // Status is how the spinner stopped, e.g. "success"
+	Text   string `json:"text,omitempty"`   // Text is the text of the spinner, or the text it stopped with
+}
+
+// writeJSON writes out the event as a line of JSON.
+// Caller must already hold s.lock.
+func (s *Spinner) writeJSON(e jsonEvent) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	if _, err := s.out().Write(append(b, '\n')); err != nil {
+		s.writeFailed(err)
+	}
+}
+
+// startJSON writes out a start event instead of animating the spinner.
+// Caller must already hold s.lock.
+func (s *Spinner) startJSON() {
+	if s.active {
+		return
+	}
+	s.active = true
+	s.startTime = time.Now()
+	s.writeJSON(jsonEvent{Event: "start", Text: s.Text})
+	s.emit(EventStarted, 0, s.Text)
+}
+
+// static reports whether the spinner writes out lines instead of animating,
+// in a GitHub Actions log or as JSON.
+// Caller must already hold s.lock.
+func (s *Spinner) static() bool {
+	return s.githubActions || s.JSONOutput
+}
@@ -0,0 +1,24 @@
+package spintron
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJSONOutput(t *testing.T) {
+	var buf syncBuffer
+	s := New(Options{Writer: &buf, JSONOutput: true, Text: "one"})
+	s.Start()
+	s.SetText("two")
+	s.SetText("two")
+	s.Succeed("done")
+
+	want := []string{
+		`{"event":"start","text":"one"}`,
+		`{"event":"text","text":"two"}`,
+		`{"event":"stop","status":"success","text":"done"}`,
+	}
+	if got := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("lines %q, want %q", got, want)
+	}
+}
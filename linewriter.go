@@ -0,0 +1,44 @@
+package spintron
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// lineWriter writes the lines written to it above a spinner.
+type lineWriter struct {
+	s   *Spinner
+	mu  sync.Mutex
+	buf []byte // buf holds the start of a line until its line break is written
+}
+
+// LineWriter returns a writer which writes every line written to it above the
+// spinner through WriteAbove, so output of other code, e.g. through
+// log.SetOutput(s.LineWriter()), doesn't smear the spinner. A line is held
+// until its line break is written. Every call returns a new writer, with a
+// buffer of its own.
+func (s *Spinner) LineWriter() io.Writer {
+	return &lineWriter{s: s}
+}
+
+// Write writes the complete lines in p above the spinner and holds on to the
+// rest.
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.s.WriteAbove(string(bytes.TrimSuffix(w.buf[:i], []byte("\r"))))
+		w.buf = w.buf[i+1:]
+	}
+	if len(w.buf) == 0 {
+		// let go of the memory of long lines written before
+		w.buf = nil
+	}
+	return len(p), nil
+}
@@ -0,0 +1,33 @@
+package spintron
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestLineWriter(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"-"}, Text: "working"})
+	s.Start()
+	s.RenderNextFrame()
+	buf.Reset()
+
+	w := s.LineWriter()
+	fmt.Fprint(w, "first ")
+	if buf.String() != "" {
+		t.Errorf("wrote %q before the line break", buf.String())
+	}
+	fmt.Fprint(w, "line\r\nsecond line\nrest")
+	if want := "\r\x1b[K\rfirst line\n\r- working\r\x1b[K\rsecond line\n\r- working"; buf.String() != want {
+		t.Errorf("wrote %q, want the lines above the spinner %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	logger := log.New(s.LineWriter(), "", 0)
+	logger.Print("logged")
+	s.Stop()
+	if !strings.HasPrefix(buf.String(), "\r\x1b[K\rlogged\n\r- working") {
+		t.Errorf("logger wrote %q, want the line above the spinner", buf.String())
+	}
+}
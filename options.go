@@ -0,0 +1,64 @@
+package spintron
+
+import (
+	"io"
+	"time"
+)
+
+// Option configures a spinner created by NewWithOptions.
+type Option func(*Spinner)
+
+// NewWithOptions provides a pointer to an instance of Spinner with the defaults
+// of New, changed by the given options in order.
+func NewWithOptions(opts ...Option) *Spinner {
+	s := New(Options{})
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// WithColor sets the color of the spinner. Invalid colors are ignored.
+func WithColor(colors ...string) Option {
+	return func(s *Spinner) {
+		s.Color(colors...)
+	}
+}
+
+// WithText sets the text shown after the spinner.
+func WithText(text string) Option {
+	return func(s *Spinner) {
+		s.SetText(text)
+	}
+}
+
+// WithDelay sets the delay between frames.
+func WithDelay(d time.Duration) Option {
+	return func(s *Spinner) {
+		s.UpdateSpeed(d)
+	}
+}
+
+// WithCharSet sets the character set of the spinner.
+func WithCharSet(cs []string) Option {
+	return func(s *Spinner) {
+		s.UpdateCharSet(cs)
+	}
+}
+
+// WithHideCursor sets whether the cursor is hidden while the spinner runs.
+func WithHideCursor(hide bool) Option {
+	return func(s *Spinner) {
+		s.mu.Lock()
+		s.HideCursor = hide
+		s.mu.Unlock()
+	}
+}
+
+// WithWriterOpt sets the writer the spinner writes to. It's named apart from
+// the WithWriter method of Spinner, which moves a running spinner.
+func WithWriterOpt(w io.Writer) Option {
+	return func(s *Spinner) {
+		s.WithWriter(w)
+	}
+}
@@ -0,0 +1,38 @@
+package spintron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	withColor(t)
+	w := &syncBuffer{}
+	s := NewWithOptions(
+		WithColor("red"),
+		WithText("configured"),
+		WithDelay(250*time.Millisecond),
+		WithCharSet([]string{"a", "b"}),
+		WithHideCursor(false),
+		WithWriterOpt(w),
+	)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.Text != "configured" || s.Delay != 250*time.Millisecond || s.HideCursor || s.Writer != w {
+		t.Errorf("options not all applied: text %q, delay %v, hide cursor %t", s.Text, s.Delay, s.HideCursor)
+	}
+	if !sameFrames(s.chars, []string{"a", "b"}) {
+		t.Errorf("character set %q, want a and b", s.chars)
+	}
+	if got := s.color("x"); got != "\x1b[31mx\x1b[0m" {
+		t.Errorf("color renders %q, want red", got)
+	}
+}
+
+func TestNewWithOptionsDefaults(t *testing.T) {
+	s, def := NewWithOptions(), New(Options{})
+	if s.Delay != def.Delay || !sameFrames(s.Frames(), def.Frames()) || s.HideCursor != def.HideCursor {
+		t.Error("NewWithOptions without options differs from New")
+	}
+}
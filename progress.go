@@ -0,0 +1,142 @@
+package spintron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxETA is the longest time remaining that is shown, anything longer
+	// isn't a useful estimate.
+	maxETA = 100 * time.Hour
+	// defaultBarWidth is the width of the progress bar unless set otherwise.
+	defaultBarWidth = 40
+	// minBarWidth is the narrowest an automatically sized progress bar gets.
+	minBarWidth = 10
+	// barDecoration is the width taken by the brackets and the percentage
+	// around the progress bar.
+	barDecoration = len("[] 100%")
+)
+
+// SetProgress sets how far along the task is. While the total is positive the
+// percentage done and the estimated time remaining are shown after the text.
+func (s *Spinner) SetProgress(current, total int64) {
+	s.mu.Lock()
+	s.current = current
+	s.total = total
+	s.reportProgress()
+	s.mu.Unlock()
+}
+
+// progress returns how far along the task is, bounded by zero and the total.
+// Caller must already hold s.lock.
+func (s *Spinner) progress() int64 {
+	switch {
+	case s.current < 0:
+		return 0
+	case s.current > s.total:
+		return s.total
+	}
+	return s.current
+}
+
+// progressSuffix returns the percentage done and the estimated time remaining
+// to show after the text, if progress is set. The percentage is left out when
+// it's already shown by the progress bar.
+// Caller must already hold s.lock.
+func (s *Spinner) progressSuffix() string {
+	if s.total <= 0 {
+		return ""
+	}
+	current := s.progress()
+	var elapsed time.Duration
+	if !s.startTime.IsZero() {
+		elapsed = time.Since(s.startTime)
+	}
+	if s.ProgressBar {
+		return fmt.Sprintf(" (ETA %s)", s.eta(current, elapsed))
+	}
+	percent := current * 100 / s.total
+	return fmt.Sprintf(" %d%% (ETA %s)", percent, s.eta(current, elapsed))
+}
+
+// barShown reports whether the progress bar is shown in place of the
+// character, which it isn't on a terminal too narrow for it.
+// Caller must already hold s.lock.
+func (s *Spinner) barShown() bool {
+	return s.ProgressBar && s.total > 0 && !s.narrow()
+}
+
+// progressBar draws the progress as a bar followed by the percentage done, e.g.
+// "[=====>    ] 45%". The other segments of the line take up rest columns.
+// Caller must already hold s.lock.
+func (s *Spinner) progressBar(rest int) string {
+	width := s.barWidth(rest)
+
+	current := s.progress()
+	filled := int(current * int64(width) / s.total)
+
+	var bar string
+	if filled >= width {
+		bar = strings.Repeat("=", width)
+	} else {
+		bar = strings.Repeat("=", filled) + ">" + strings.Repeat(" ", width-filled-1)
+	}
+	return fmt.Sprintf("[%s] %d%%", bar, current*100/s.total)
+}
+
+// barWidth returns the width of the progress bar: BarWidth, or with
+// BarWidthAuto what's left of the terminal width after the other segments,
+// which take up rest columns.
+// Caller must already hold s.lock.
+func (s *Spinner) barWidth(rest int) int {
+	width := s.BarWidth
+	if s.BarWidthAuto && s.termWidth > 0 {
+		// leave the last column free so the line never wraps, however
+		// little room the other segments leave
+		width = s.termWidth - rest - barDecoration - 1
+		if width < minBarWidth {
+			width = minBarWidth
+		}
+	}
+	if width <= 0 {
+		width = defaultBarWidth
+	}
+	return width
+}
+
+// eta estimates the time remaining from the average progress rate so far.
+// Caller must already hold s.lock.
+func (s *Spinner) eta(current int64, elapsed time.Duration) string {
+	if current <= 0 || elapsed <= 0 {
+		return "--"
+	}
+	rate := float64(current) / elapsed.Seconds()
+	remaining := float64(s.total-current) / rate
+	if remaining > maxETA.Seconds() {
+		return "--"
+	}
+	return time.Duration(remaining * float64(time.Second)).Round(time.Second).String()
+}
+
+// reportProgress writes the percentage done and the text to ProgressFD as a
+// "percent:text" line whenever either changed. The percentage is left empty
+// while the total is unknown.
+// Caller must already hold s.lock.
+func (s *Spinner) reportProgress() {
+	if s.ProgressFD == nil {
+		return
+	}
+	var percent string
+	if s.total > 0 {
+		percent = strconv.FormatInt(s.progress()*100/s.total, 10)
+	}
+	report := percent + ":" + sanitize(s.Text) + "\n"
+	if report == s.lastReport {
+		return
+	}
+	s.lastReport = report
+	s.ProgressFD.WriteString(report)
+}
@@ -0,0 +1,161 @@
+package spintron
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgressBar(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Managed: true, ProgressBar: true, BarWidth: 10})
+	s.SetProgress(50, 100)
+	s.Start()
+	s.RenderNextFrame()
+	s.Stop()
+
+	if want := "[=====>    ] 50%"; !strings.Contains(buf.String(), want) {
+		t.Errorf("output %q doesn't contain %q", buf.String(), want)
+	}
+}
+
+func TestProgressOutOfRange(t *testing.T) {
+	tests := []struct {
+		current int64
+		bar     string
+		suffix  string
+	}{
+		{-50, "[>         ] 0%", " 0%"},
+		{150, "[==========] 100%", " 100%"},
+	}
+	for _, tt := range tests {
+		s, buf := newTestSpinner(t, Options{Managed: true, ProgressBar: true, BarWidth: 10})
+		s.SetProgress(tt.current, 100)
+		s.Start()
+		s.RenderNextFrame()
+		s.Stop()
+		if !strings.Contains(buf.String(), tt.bar) {
+			t.Errorf("SetProgress(%d, 100): output %q doesn't contain %q", tt.current, buf.String(), tt.bar)
+		}
+
+		s.mu.Lock()
+		s.ProgressBar = false
+		suffix := s.progressSuffix()
+		s.mu.Unlock()
+		if !strings.HasPrefix(suffix, tt.suffix+" ") {
+			t.Errorf("SetProgress(%d, 100): suffix %q doesn't start with %q", tt.current, suffix, tt.suffix)
+		}
+	}
+}
+
+func TestProgressBarWidthAuto(t *testing.T) {
+	for _, width := range []int{40, 80} {
+		s, _ := newTestSpinner(t, Options{ProgressBar: true, BarWidthAuto: true})
+		s.SetProgress(1, 2)
+		s.mu.Lock()
+		s.termWidth = width
+		out, _ := s.compose()
+		s.mu.Unlock()
+		// the bar leaves room for "100%", so at 50% it comes up one short
+		if got := displayWidth(out); got != width-2 {
+			t.Errorf("terminal width %d: line %q is %d columns wide, want %d", width, out, got, width-2)
+		}
+	}
+}
+
+func TestProgressBarWidthAutoResize(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Managed: true, ProgressBar: true, BarWidthAuto: true})
+	s.SetProgress(2, 2)
+	s.Start()
+	defer s.Stop()
+
+	// the bar follows the width the terminal is resized to from one frame
+	// to the next
+	for _, width := range []int{60, 30, 90} {
+		s.mu.Lock()
+		s.termWidth = width
+		s.mu.Unlock()
+		s.RenderNextFrame()
+		if got := displayWidth(lastFrame(s)); got != width-1 {
+			t.Errorf("terminal width %d: line %q is %d columns wide, want %d", width, lastFrame(s), got, width-1)
+		}
+	}
+}
+
+func TestProgressBarWidthAutoSIGWINCH(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Managed: true, ProgressBar: true, BarWidthAuto: true})
+	s.SetProgress(2, 2)
+	s.Start()
+	defer s.Stop()
+
+	// the bar is redrawn on the resize itself, without waiting for a frame
+	for _, width := range []int{60, 30} {
+		s.mu.Lock()
+		s.resizeTo(width)
+		s.mu.Unlock()
+		if got := displayWidth(lastFrame(s)); got != width-1 {
+			t.Errorf("resized to %d: line %q is %d columns wide, want %d", width, lastFrame(s), got, width-1)
+		}
+	}
+}
+
+func TestProgressETA(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Managed: true, Text: "copying"})
+	s.SetProgress(0, 100)
+	if got := s.eta(0, time.Second); got != "--" {
+		t.Errorf("no progress yet: ETA %q, want --", got)
+	}
+	// 10 items a second, on a clock driven by the test
+	for elapsed := 1; elapsed <= 9; elapsed += 4 {
+		current := int64(elapsed * 10)
+		want := (time.Duration(10-elapsed) * time.Second).String()
+		if got := s.eta(current, time.Duration(elapsed)*time.Second); got != want {
+			t.Errorf("%d done after %ds: ETA %q, want %q", current, elapsed, got, want)
+		}
+	}
+	if got := s.eta(1, 2*time.Hour); got != "--" {
+		t.Errorf("hopelessly slow: ETA %q, want --", got)
+	}
+
+	s.Start()
+	s.mu.Lock()
+	s.startTime = time.Now().Add(-10 * time.Second)
+	s.mu.Unlock()
+	s.SetProgress(25, 100)
+	s.RenderNextFrame()
+	if got := s.LastOutput(); !strings.HasSuffix(got, "copying 25% (ETA 30s)") {
+		t.Errorf("frame %q doesn't show an ETA of 30s", got)
+	}
+	s.Stop()
+}
+
+func TestProgressFD(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	s, buf := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"-"}, Text: "starting"})
+	s.ProgressFD = w
+	s.Start()
+	s.SetProgress(1, 4)
+	s.SetText("halfway")
+	s.SetProgress(2, 4)
+	s.SetProgress(2, 4)
+	s.RenderNextFrame()
+	s.Stop()
+	w.Close()
+
+	report, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(report), "25:starting\n25:halfway\n50:halfway\n"; got != want {
+		t.Errorf("progress reports %q, want %q", got, want)
+	}
+	if !strings.Contains(buf.String(), "- halfway 50%") || strings.Contains(buf.String(), "50:") {
+		t.Errorf("writer got %q, want the frames only", buf.String())
+	}
+}
@@ -0,0 +1,38 @@
+package spintron
+
+import "strconv"
+
+// SetQueueDepth sets the number of queued items shown after the text, e.g.
+// "Working (queue: 42)".
+func (s *Spinner) SetQueueDepth(n int) {
+	s.mu.Lock()
+	s.queueDepth = n
+	s.queueShown = true
+	s.mu.Unlock()
+}
+
+// IncQueue adds one item to the queue depth shown after the text.
+func (s *Spinner) IncQueue() {
+	s.addQueue(1)
+}
+
+// DecQueue removes one item from the queue depth shown after the text.
+func (s *Spinner) DecQueue() {
+	s.addQueue(-1)
+}
+
+func (s *Spinner) addQueue(n int) {
+	s.mu.Lock()
+	s.queueDepth += n
+	s.queueShown = true
+	s.mu.Unlock()
+}
+
+// queueSuffix returns the queue depth to show after the text, if any.
+// Caller must already hold s.lock.
+func (s *Spinner) queueSuffix() string {
+	if !s.queueShown {
+		return ""
+	}
+	return " (queue: " + strconv.Itoa(s.queueDepth) + ")"
+}
@@ -0,0 +1,34 @@
+package spintron
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueueDepthConcurrent(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Delay: time.Millisecond, CharacterSet: []string{"-"}, Text: "Working"})
+	s.Start()
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				s.IncQueue()
+				if i%2 == 0 {
+					s.DecQueue()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	s.mu.Lock()
+	out, _ := s.compose()
+	s.mu.Unlock()
+	s.Stop()
+
+	if got, want := stripANSI(out), "\r- Working (queue: 400)"; got != want {
+		t.Errorf("frame %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,73 @@
+package spintron
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// rateInterval is how often the items rate is sampled.
+	rateInterval = 500 * time.Millisecond
+	// rateSmoothing is the weight given to the newest sample of the rate.
+	rateSmoothing = 0.3
+)
+
+// RecordItems adds the given number of processed items to the spinner. Once
+// items are recorded, the smoothed rate of items per second is shown after the
+// text.
+func (s *Spinner) RecordItems(n int) {
+	s.mu.Lock()
+	if s.rateTime.IsZero() {
+		s.rateTime = time.Now()
+	}
+	s.rateItems += n
+	s.mu.Unlock()
+}
+
+// updateRate folds the items recorded since the last sample into the
+// exponential moving average of the rate.
+// Caller must already hold s.lock.
+func (s *Spinner) updateRate(now time.Time) {
+	if s.rateTime.IsZero() {
+		return
+	}
+	elapsed := now.Sub(s.rateTime)
+	if elapsed < rateInterval {
+		return
+	}
+	sample := float64(s.rateItems) / elapsed.Seconds()
+	if s.rateSampled {
+		s.rate = rateSmoothing*sample + (1-rateSmoothing)*s.rate
+	} else {
+		s.rate = sample
+		s.rateSampled = true
+	}
+	s.rateItems = 0
+	s.rateTime = now
+}
+
+// rateSuffix returns the rate of items to show after the text, if any.
+// Caller must already hold s.lock.
+func (s *Spinner) rateSuffix() string {
+	s.updateRate(time.Now())
+	if !s.rateSampled {
+		return ""
+	}
+	return " (" + formatRate(s.rate) + ")"
+}
+
+// formatRate formats a number of items per second in a short form, e.g. 1.2k/s.
+func formatRate(rate float64) string {
+	switch {
+	case rate >= 1e9:
+		return fmt.Sprintf("%.1fG/s", rate/1e9)
+	case rate >= 1e6:
+		return fmt.Sprintf("%.1fM/s", rate/1e6)
+	case rate >= 1e3:
+		return fmt.Sprintf("%.1fk/s", rate/1e3)
+	case rate >= 10:
+		return fmt.Sprintf("%.0f/s", rate)
+	default:
+		return fmt.Sprintf("%.1f/s", rate)
+	}
+}
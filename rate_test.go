@@ -0,0 +1,49 @@
+package spintron
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRate(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"-"}, Text: "indexing"})
+	start := time.Now()
+	s.RecordItems(0)
+	s.mu.Lock()
+	s.rateTime = start
+	s.mu.Unlock()
+
+	// 50 items every half second, fed through a clock driven by the test
+	for i := 1; i <= 4; i++ {
+		s.RecordItems(50)
+		s.mu.Lock()
+		s.updateRate(start.Add(time.Duration(i) * rateInterval))
+		s.mu.Unlock()
+	}
+	s.Start()
+	s.RenderNextFrame()
+	defer s.Stop()
+
+	if got := lastFrame(s); !strings.HasSuffix(got, "indexing (100/s)") {
+		t.Errorf("frame %q doesn't show a rate of 100/s", got)
+	}
+}
+
+func TestFormatRate(t *testing.T) {
+	tests := []struct {
+		rate float64
+		want string
+	}{
+		{0.5, "0.5/s"},
+		{42, "42/s"},
+		{1234, "1.2k/s"},
+		{5.5e6, "5.5M/s"},
+		{2e9, "2.0G/s"},
+	}
+	for _, tt := range tests {
+		if got := formatRate(tt.rate); got != tt.want {
+			t.Errorf("formatRate(%v) = %q, want %q", tt.rate, got, tt.want)
+		}
+	}
+}
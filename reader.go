@@ -0,0 +1,130 @@
+package spintron
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// progressReader updates a spinner's progress as bytes are read through it.
+type progressReader struct {
+	rc    io.ReadCloser
+	s     *Spinner
+	read  int64
+	total int64
+}
+
+// WrapReader returns a reader which updates the spinner's progress and byte
+// count as it's read from, and stops the spinner when closed. Pass a total of
+// 0 or less when the size isn't known, then only the byte count is shown.
+func (s *Spinner) WrapReader(rc io.ReadCloser, total int64) io.ReadCloser {
+	s.mu.Lock()
+	s.showBytes = true
+	s.resetBytes()
+	s.current = 0
+	s.total = total
+	s.mu.Unlock()
+	return &progressReader{rc: rc, s: s, total: total}
+}
+
+// Read reads from the wrapped reader and updates the spinner.
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.rc.Read(p)
+	r.read += int64(n)
+	r.s.mu.Lock()
+	r.s.addBytes(int64(n))
+	r.s.current = r.read
+	r.s.mu.Unlock()
+	return n, err
+}
+
+// Close closes the wrapped reader and stops the spinner.
+func (r *progressReader) Close() error {
+	err := r.rc.Close()
+	r.s.Stop()
+	return err
+}
+
+// AddBytes adds the given number of bytes to the bytes transferred. With
+// ShowBytes set they're shown after the text along with the smoothed rate,
+// e.g. " 12.4 MiB (3.1 MiB/s)".
+func (s *Spinner) AddBytes(n int64) {
+	s.mu.Lock()
+	s.addBytes(n)
+	s.mu.Unlock()
+}
+
+// addBytes adds to the bytes transferred and to the bytes of the next sample
+// of the rate.
+// Caller must already hold s.lock.
+func (s *Spinner) addBytes(n int64) {
+	if s.byteRateTime.IsZero() {
+		s.byteRateTime = time.Now()
+	}
+	s.bytes += n
+	s.byteRateBytes += n
+}
+
+// resetBytes sets the bytes transferred, and their rate, back to zero.
+// Caller must already hold s.lock.
+func (s *Spinner) resetBytes() {
+	s.bytes = 0
+	s.byteRateTime = time.Time{}
+	s.byteRateBytes = 0
+	s.byteRate = 0
+	s.byteRateSampled = false
+}
+
+// updateByteRate folds the bytes added since the last sample into the
+// exponential moving average of the rate, like updateRate does for items.
+// Caller must already hold s.lock.
+func (s *Spinner) updateByteRate(now time.Time) {
+	if s.byteRateTime.IsZero() {
+		return
+	}
+	elapsed := now.Sub(s.byteRateTime)
+	if elapsed < rateInterval {
+		return
+	}
+	sample := float64(s.byteRateBytes) / elapsed.Seconds()
+	if s.byteRateSampled {
+		s.byteRate = rateSmoothing*sample + (1-rateSmoothing)*s.byteRate
+	} else {
+		s.byteRate = sample
+		s.byteRateSampled = true
+	}
+	s.byteRateBytes = 0
+	s.byteRateTime = now
+}
+
+// bytesSuffix returns the number of bytes transferred to show after the text,
+// along with the total if it's known and the rate once it's sampled.
+// Caller must already hold s.lock.
+func (s *Spinner) bytesSuffix() string {
+	if !s.showBytes {
+		return ""
+	}
+	suffix := " " + formatBytes(s.bytes)
+	if s.total > 0 {
+		suffix += "/" + formatBytes(s.total)
+	}
+	s.updateByteRate(time.Now())
+	if s.byteRateSampled {
+		suffix += " (" + formatBytes(int64(s.byteRate)) + "/s)"
+	}
+	return suffix
+}
+
+// formatBytes formats a number of bytes using binary units, e.g. 12.4 MiB.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 5; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,61 @@
+package spintron
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestWrapReader(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"-"}, Text: "downloading"})
+	s.Start()
+	r := s.WrapReader(ioutil.NopCloser(bytes.NewReader(make([]byte, 2048))), 2048)
+
+	p := make([]byte, 512)
+	for _, want := range []string{" 512 B/2.0 KiB 25%", " 1.0 KiB/2.0 KiB 50%", " 1.5 KiB/2.0 KiB 75%", " 2.0 KiB/2.0 KiB 100%"} {
+		if _, err := io.ReadFull(r, p); err != nil {
+			t.Fatal(err)
+		}
+		s.RenderNextFrame()
+		if got := s.LastOutput(); !strings.HasPrefix(got, "- downloading"+want) {
+			t.Errorf("frame %q, want it to show %q", got, want)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if s.Active() {
+		t.Error("spinner still runs after the reader was closed")
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1536, "1.5 KiB"},
+		{13002342, "12.4 MiB"},
+		{1 << 40, "1.0 TiB"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.n); got != tt.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestAddBytes(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"-"}, Text: "copying", ShowBytes: true})
+	s.Start()
+	s.AddBytes(1536)
+	s.RenderNextFrame()
+	defer s.Stop()
+	if got := s.LastOutput(); !strings.HasPrefix(got, "- copying 1.5 KiB") {
+		t.Errorf("frame %q, want the bytes counted so far", got)
+	}
+}
@@ -0,0 +1,24 @@
+package spintron
+
+import "strings"
+
+// SetRTL will lay out the line right-to-left, for text in scripts such as
+// Arabic or Hebrew: the text comes first, followed by the spinner character,
+// the prefix text and the symbol.
+func (s *Spinner) SetRTL(rtl bool) {
+	s.mu.Lock()
+	s.RTL = rtl
+	s.mu.Unlock()
+}
+
+// rtlLine joins the segments of a right-to-left line, given in the order
+// they're shown, with a space between those that aren't empty.
+func rtlLine(segments ...string) string {
+	parts := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if segment = strings.Trim(segment, " "); stripANSI(segment) != "" {
+			parts = append(parts, segment)
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,26 @@
+package spintron
+
+import "testing"
+
+func TestRTL(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"-"}, Symbol: ">", PrefixText: "job", Text: "שלום 漢字", RTL: true})
+	s.Start()
+	s.RenderNextFrame()
+	if got, want := s.LastOutput(), "שלום 漢字 - job >"; got != want {
+		t.Errorf("frame %q, want the text first %q", got, want)
+	}
+	// the erase covers every cell of the line, the wide characters too
+	s.mu.RLock()
+	width := displayWidth(s.lastOutput)
+	s.mu.RUnlock()
+	if width != 17 {
+		t.Errorf("erase width %d, want 17", width)
+	}
+
+	s.SetRTL(false)
+	s.RenderNextFrame()
+	defer s.Stop()
+	if got, want := s.LastOutput(), "> job - שלום 漢字"; got != want {
+		t.Errorf("frame %q after SetRTL(false), want the usual layout %q", got, want)
+	}
+}
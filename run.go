@@ -0,0 +1,62 @@
+package spintron
+
+import "time"
+
+// RunDeferred runs fn and only shows the spinner if fn takes longer than the
+// given delay. Either way, once fn returns the spinner is stopped with the
+// success text, or with the failure text if fn returned an error, which is
+// returned as is.
+func RunDeferred(s *Spinner, delay time.Duration, successText, failText string, fn func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+
+	timer := time.NewTimer(delay)
+	var err error
+	select {
+	case err = <-done:
+		timer.Stop()
+	case <-timer.C:
+		s.Start()
+		err = <-done
+	}
+
+	if err != nil {
+		s.Fail(failText)
+	} else {
+		s.Succeed(successText)
+	}
+	return err
+}
+
+// Run starts the spinner, runs fn and stops the spinner with a success sign and
+// its text, or with a failure sign and the error if fn returned one, which is
+// returned as is. If fn panics the spinner is stopped, restoring the cursor,
+// before the panic carries on.
+func (s *Spinner) Run(fn func() error) (err error) {
+	s.Start()
+	defer func() {
+		if r := recover(); r != nil {
+			s.Stop()
+			panic(r)
+		}
+		if err != nil {
+			s.Fail(err.Error())
+		} else {
+			s.mu.RLock()
+			text := s.Text
+			s.mu.RUnlock()
+			s.Succeed(text)
+		}
+	}()
+	return fn()
+}
+
+// Run runs fn with the default spinner showing the given text, like the Run
+// method of Spinner.
+func Run(text string, fn func() error) error {
+	s := Default()
+	s.SetText(text)
+	return s.Run(fn)
+}
@@ -0,0 +1,89 @@
+package spintron
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// resultLine returns the last line written to the buffer, without styling.
+func resultLine(out string) string {
+	out = stripANSI(strings.TrimSuffix(out, "\n"))
+	if i := strings.LastIndexAny(out, "\r\n"); i >= 0 {
+		out = out[i+1:]
+	}
+	return out
+}
+
+func TestRunDeferred(t *testing.T) {
+	fast, fastBuf := newTestSpinner(t, Options{Delay: time.Millisecond, CharacterSet: []string{"-"}, Text: "working"})
+	err := RunDeferred(fast, time.Second, "done", "failed", func() error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	slow, slowBuf := newTestSpinner(t, Options{Delay: time.Millisecond, CharacterSet: []string{"-"}, Text: "working"})
+	err = RunDeferred(slow, 5*time.Millisecond, "done", "failed", func() error {
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(fastBuf.String(), "working") {
+		t.Errorf("fast function showed the spinner: %q", fastBuf.String())
+	}
+	if !strings.Contains(slowBuf.String(), "- working") {
+		t.Errorf("slow function didn't show the spinner: %q", slowBuf.String())
+	}
+	if a, b := resultLine(fastBuf.String()), resultLine(slowBuf.String()); a != b || !strings.HasSuffix(a, " done") {
+		t.Errorf("result lines %q and %q, want the same success line", a, b)
+	}
+}
+
+func TestRunDeferredError(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{})
+	want := errors.New("boom")
+	if err := RunDeferred(s, time.Second, "done", "failed", func() error { return want }); err != want {
+		t.Errorf("error %v, want %v", err, want)
+	}
+	if got := resultLine(buf.String()); !strings.HasSuffix(got, " failed") {
+		t.Errorf("result line %q, want the failure text", got)
+	}
+}
+
+func TestRun(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Delay: time.Millisecond, CharacterSet: []string{"-"}, Text: "working", SuccessSymbol: "+", FailureSymbol: "x"})
+	if err := s.Run(func() error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if got := resultLine(buf.String()); got != "+ working" {
+		t.Errorf("success: result line %q, want %q", got, "+ working")
+	}
+
+	buf.Reset()
+	want := errors.New("boom")
+	if err := s.Run(func() error { return want }); err != want {
+		t.Errorf("error %v, want %v", err, want)
+	}
+	if got := resultLine(buf.String()); got != "x boom" {
+		t.Errorf("error: result line %q, want %q", got, "x boom")
+	}
+}
+
+func TestRunPanic(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Delay: time.Millisecond, CharacterSet: []string{"-"}, HideCursor: true})
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("recovered %v, want the panic of the function", r)
+		}
+		if s.Active() {
+			t.Error("spinner still active after the panic")
+		}
+		if !strings.HasSuffix(buf.String(), "\x1b[?25h\r\x1b[K") {
+			t.Errorf("output %q doesn't end with the cursor shown and the line erased", buf.String())
+		}
+	}()
+	s.Run(func() error { panic("boom") })
+}
@@ -16,57 +16,220 @@
 package spintron
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
+	"math/rand"
 	"os"
 	"runtime"
 	"strconv"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
-	"unicode/utf8"
 
 	logSymbols "github.com/defaltd/log-symbols"
 	"github.com/fatih/color"
 	"github.com/mattn/go-isatty"
 )
 
-// returns true if the OS is windows and the WT_SESSION env variable is set.
+// resetSequence resets all colors and attributes.
+const resetSequence = "\033[0m"
+
+// AnimatedSegment is a part of the line that can be animated by the character
+// set.
+type AnimatedSegment int
+
+const (
+	// AnimateGlyph animates the character between the prefix text and the text.
+	AnimateGlyph AnimatedSegment = iota
+	// AnimatePrefix animates a character before the prefix text.
+	AnimatePrefix
+	// AnimateSuffix animates a character at the end of the text.
+	AnimateSuffix
+)
+
+// minDelay is the delay used between frames when Delay isn't positive.
+const minDelay = time.Millisecond
+
+// defaultMinInterval caps redraws at about 60 per second.
+const defaultMinInterval = 16 * time.Millisecond
+
+// narrowWidth is the terminal width below which only the character is shown.
+const narrowWidth = 10
+
+// returns true if the OS is windows and the WT_SESSION env variable is set,
+// or, see vtOnce, the console processes escape sequences.
 var isWindowsTerminalOnWindows = len(os.Getenv("WT_SESSION")) > 0 && runtime.GOOS == "windows"
 
+// vtOnce turns on escape sequence processing on Windows consoles the first time
+// a spinner is created.
+var vtOnce sync.Once
+
 // Spinner struct to hold the provided options.
 type Spinner struct {
-	mu                 *sync.RWMutex
-	Delay              time.Duration                 // Delay is the speed of the indicator
-	chars              []string                      // chars holds the chosen character set
-	Text               string                        // Text shown after the Spinner
-	lastOutput         string                        // last character(set) written
-	color              func(a ...interface{}) string // default color is white
-	Writer             io.Writer                     // to make testing better, exported so users have access. Use `WithWriter` to update after initialization.
-	active             bool                          // active holds the state of the spinner
-	stopChan           chan struct{}                 // stopChan is a channel used to stop the indicator
-	HideCursor         bool                          // hideCursor determines if the cursor is visible
-	PreUpdate          func(s *Spinner)              // will be triggered before every spinner update
-	PostUpdate         func(s *Spinner)              // will be triggered after every spinner update
-	Symbol             string                        // Symbol for the spinner, show before PrefixText
-	PrefixText         string                        // PrefixText for the spinner, shown before the spinner and after the Symbol
-	Padding            int                           // Padding for the spinner
-	ShowElaspedSeconds bool                          // ShowElaspedSeconds determines if the spinner should show the elapsed time
-	secondsElasped     int                           // Number of seconds elapsed since the spinner was started
+	mu                  *sync.RWMutex
+	Delay               time.Duration                            // Delay is the speed of the indicator
+	chars               []string                                 // chars holds the chosen character set
+	Text                string                                   // Text shown after the Spinner
+	lastOutput          string                                   // last character(set) written
+	lastWritten         string                                   // lastWritten is the last line written, styling included
+	Coordinated         bool                                     // Coordinated makes the spinner wait for its turn on a writer shared with other coordinated spinners
+	ForceTerminal       bool                                     // ForceTerminal animates the spinner even if Writer isn't a terminal
+	EnableNonTTY        bool                                     // EnableNonTTY writes the line once on Start, without animation, when Writer isn't a terminal
+	TrackFocus          bool                                     // TrackFocus dims the spinner while the terminal doesn't have focus
+	FocusReader         io.Reader                                // FocusReader is where focus reports are read from, os.Stdin by default
+	input               *focusInput                              // input is FocusReader without the focus reports, read while focus is tracked
+	unfocused           bool                                     // unfocused reports whether the terminal lost focus
+	coordinator         *Coordinator                             // coordinator is the coordinator of the writer while the spinner holds it
+	color               func(a ...interface{}) string            // default color is white
+	Writer              io.Writer                                // to make testing better, exported so users have access. Use `WithWriter` to update after initialization.
+	active              bool                                     // active holds the state of the spinner
+	stopChan            chan struct{}                            // stopChan is closed to stop the indicator, made anew on every start
+	HideCursor          bool                                     // hideCursor determines if the cursor is visible
+	PreUpdate           func(s *Spinner)                         // will be triggered before every spinner update, free to call the methods of the spinner
+	PostUpdate          func(s *Spinner)                         // will be triggered after every spinner update, free to call the methods of the spinner
+	CallbackTimeout     time.Duration                            // CallbackTimeout is the time PreUpdate and PostUpdate may take before it's reported to DebugLog
+	PersistWithoutErase bool                                     // PersistWithoutErase leaves out the carriage return and erase before persisted lines
+	PersistBlink        int                                      // PersistBlink is the number of times the persisted symbol blinks before it's left steady
+	Symbol              string                                   // Symbol for the spinner, show before PrefixText
+	PrefixText          string                                   // PrefixText for the spinner, shown before the spinner and after the Symbol
+	Padding             int                                      // Padding for the spinner
+	Indent              int                                      // Indent is the number of spaces before the spinner and persisted lines, for nesting
+	ShowElaspedSeconds  bool                                     // ShowElaspedSeconds determines if the spinner should show the elapsed time
+	Timeout             time.Duration                            // Timeout is how long the spinner may run before it stops by itself, 0 for no limit
+	FinalMSG            string                                   // FinalMSG is written out on a line of its own once Stop erased the spinner
+	ShowElapsed         bool                                     // ShowElapsed shows the time since the spinner started after the text, e.g. " (1m12s)"
+	HandleSuspend       bool                                     // HandleSuspend restores the terminal when the process is suspended (Ctrl-Z) and redraws on resume
+	RTL                 bool                                     // RTL lays out the line right-to-left, the text first and the symbol last
+	MinInterval         time.Duration                            // MinInterval is the least time between redraws, frames advanced meanwhile aren't written out
+	HandleInterrupt     bool                                     // HandleInterrupt restores the cursor and erases the spinner when the process is interrupted (Ctrl-C) or terminated
+	MinDisplayDuration  time.Duration                            // MinDisplayDuration is the minimum time the spinner stays on screen once it has drawn a frame
+	CaptureFrames       int                                      // CaptureFrames is the number of recently rendered frames kept for CapturedFrames
+	TimeBasedFrames     bool                                     // TimeBasedFrames picks the frame from the wall clock instead of counting ticks
+	DebugLog            func(format string, args ...interface{}) // DebugLog receives messages about internal transitions, for diagnosing issues
+	startTime           time.Time                                // startTime is when the spinner was last started
+	ResultLog           io.Writer                                // ResultLog receives a JSON record for every persisted status line
+	DebugWriter         io.Writer                                // DebugWriter receives a copy of everything written to Writer, escape sequences included
+	ProgressFD          *os.File                                 // ProgressFD receives a "percent:text" line whenever the progress or text changes
+	lastReport          string                                   // lastReport is the last line written to ProgressFD
+	events              chan Event                               // events publishes lifecycle events, nil until Events is called
+	eventText           string                                   // eventText is the text of the last EventTextChanged
+	eventQueue          []Event                                  // eventQueue holds the events waiting for room in events under the Block policy
+	eventSending        bool                                     // eventSending reports whether a goroutine is sending eventQueue
+	eventRoom           chan struct{}                            // eventRoom is closed once an event of a full eventQueue was sent
+	termWidth           int                                      // termWidth is the width of the terminal, 0 if unknown
+	sigChan             chan os.Signal                           // sigChan receives the terminal signals handled while active
+	frame               int                                      // frame is the index of the character currently shown
+	step                int                                      // step is the index of the character to show next
+	reversed            bool                                     // reversed reports whether the character set is animated from its end, see Reverse
+	paused              bool                                     // paused reports whether the animation is frozen by Pause
+	ProgressBar         bool                                     // ProgressBar shows a bar of the progress in place of the character while the total is known
+	BarWidth            int                                      // BarWidth is the width of the progress bar between its brackets
+	BarWidthAuto        bool                                     // BarWidthAuto sizes the progress bar to fill the width of the terminal
+	EventOverflow       OverflowPolicy                           // EventOverflow decides what happens to events when the Events channel is full
+	AnimatedSegment     AnimatedSegment                          // AnimatedSegment is the part of the line the character set animates
+	Managed             bool                                     // Managed leaves rendering the frames to calls to RenderNextFrame instead of an animation goroutine
+	textStyle           func(a ...interface{}) string            // textStyle applies the text attributes, nil if there are none
+	template            *template.Template                       // template lays out the frames, nil for the usual layout
+	statusSymbols       map[string]string                        // statusSymbols overrides the symbols of Succeed, Fail, Warn and Info by status
+	symbolColor         func(a ...interface{}) string            // symbolColor colors Symbol, nil to leave it plain
+	prefixColor         func(a ...interface{}) string            // prefixColor colors PrefixText, nil to leave it plain
+	textColor           func(a ...interface{}) string            // textColor colors Text, nil to leave it plain
+	rate                float64                                  // rate is the smoothed number of items recorded per second
+	rateSampled         bool                                     // rateSampled reports whether rate holds a sample yet
+	rateItems           int                                      // rateItems is the number of items recorded since the last rate sample
+	rateTime            time.Time                                // rateTime is when the rate was last sampled
+	queueDepth          int                                      // queueDepth is the number of queued items shown after the text
+	queueShown          bool                                     // queueShown reports whether the queue depth is shown
+	firstFrame          time.Time                                // firstFrame is when the first frame since the last start was drawn
+	lastFrame           time.Time                                // lastFrame is when the last frame was drawn
+	framesDrawn         int                                      // framesDrawn is the number of frames drawn since the last start
+	captured            []string                                 // captured is the ring buffer of recently rendered frames
+	captureNext         int                                      // captureNext is the position of the oldest frame in captured
+	JSONOutput          bool                                     // JSONOutput writes out a line of JSON for every change of state instead of animating
+	githubActions       bool                                     // githubActions reports whether output goes to a GitHub Actions log
+	flashing            bool                                     // flashing reports whether a flashed message replaces the text
+	flashText           string                                   // flashText is the text to restore once the flash expires
+	flashID             int                                      // flashID identifies the latest flashed message
+	staticShown         bool                                     // staticShown reports whether ShowStatic replaces the character
+	staticChar          string                                   // staticChar is the character shown by ShowStatic
+	staticID            int                                      // staticID identifies the latest call to ShowStatic
+	cycleDuration       time.Duration                            // cycleDuration is the time one pass over the character set takes, 0 if unset
+	explicitDelay       bool                                     // explicitDelay reports whether the delay was set explicitly, rather than recommended for the character set
+	suggestedDelay      time.Duration                            // suggestedDelay is the delay last recommended, which Delay differs from once it's set through the field
+	err                 error                                    // err is the error of the last failed write
+	current             int64                                    // current is how far along the task is
+	total               int64                                    // total is the size of the task, 0 if unknown
+	bytes               int64                                    // bytes is the number of bytes transferred
+	showBytes           bool                                     // showBytes determines if the number of bytes transferred is shown
+	byteRate            float64                                  // byteRate is the smoothed rate of bytes transferred per second
+	byteRateSampled     bool                                     // byteRateSampled reports whether byteRate holds a sample yet
+	byteRateBytes       int64                                    // byteRateBytes is the number of bytes added since the last rate sample
+	byteRateTime        time.Time                                // byteRateTime is when the last rate sample was taken
+	callbackTime        time.Duration                            // callbackTime is the total time spent in PreUpdate and PostUpdate
+	callbackCalls       int                                      // callbackCalls is the number of PreUpdate and PostUpdate calls timed
+	colorCond           func() bool                              // colorCond picks between colorWhenTrue and colorWhenFalse, nil if unset
+	colorWhenTrue       func(a ...interface{}) string            // colorWhenTrue is used while colorCond holds
+	colorWhenFalse      func(a ...interface{}) string            // colorWhenFalse is used while colorCond doesn't hold
+	noColor             bool                                     // noColor reports whether colors and other styling are left out
+	styledChars         []styledChar                             // styledChars caches the characters styled with color, reset when the color changes
+	palette             []func(a ...interface{}) string          // palette are the colors rotated through by SetColors, one per frame
 }
 
 // New provides a pointer to an instance of Spinner with the supplied options.
 func New(options Options) *Spinner {
+	vtOnce.Do(func() {
+		if runtime.GOOS == "windows" && enableVirtualTerminal() {
+			// consoles processing escape sequences behave like Windows Terminal
+			isWindowsTerminalOnWindows = true
+		}
+	})
+
 	s := &Spinner{
-		Delay:              100 * time.Millisecond,
+		Delay:              defaultDelay,
+		suggestedDelay:     defaultDelay,
 		chars:              CharSets["dots2"],
 		color:              color.New(color.FgCyan).SprintFunc(),
 		mu:                 &sync.RWMutex{},
 		Writer:             color.Output,
-		stopChan:           make(chan struct{}, 1),
+		stopChan:           make(chan struct{}),
 		active:             false,
 		HideCursor:         true,
 		ShowElaspedSeconds: true,
+		BarWidth:           defaultBarWidth,
+		MinInterval:        defaultMinInterval,
+		FocusReader:        os.Stdin,
+		githubActions:      isGitHubActions(),
+	}
+
+	// a theme picked by the user through the environment, explicit options still win
+	if theme, ok := Themes[os.Getenv("SPINNER_THEME")]; ok {
+		s.applyTheme(theme)
+	}
+
+	// a character set picked here brings its own delay, rather than the theme's
+	if len(options.CharacterSet) > 0 {
+		s.chars = options.CharacterSet
+		s.applyRecommendedDelay()
+	} else if cs, ok := CharSetByName(options.CharacterSetName); ok {
+		s.chars = cs
+		s.applyRecommendedDelay()
+	} else if options.RandomCharSet {
+		if options.RandomSeed != 0 {
+			s.chars = randomCharSet(rand.New(rand.NewSource(options.RandomSeed)))
+		} else {
+			s.chars = RandomCharSet()
+		}
+		s.applyRecommendedDelay()
+	}
+
+	// an empty NO_COLOR leaves the colors on, see https://no-color.org
+	if os.Getenv("NO_COLOR") != "" || options.DisableColor {
+		s.DisableColor()
 	}
 
 	if options.Writer != nil {
@@ -101,16 +264,186 @@ func New(options Options) *Spinner {
 
 	if options.Delay != 0 {
 		s.Delay = options.Delay
+		s.explicitDelay = true
+	}
+
+	if options.FPS > 0 && !math.IsInf(options.FPS, 1) {
+		s.Delay = fpsDelay(options.FPS)
+		s.explicitDelay = true
 	}
 
 	if options.Padding != 0 {
 		s.Padding = options.Padding
 	}
 
+	if options.Indent != 0 {
+		s.Indent = options.Indent
+	}
+
 	if options.DisableElaspedSeconds {
 		s.ShowElaspedSeconds = false
 	}
 
+	if options.ShowElapsed {
+		s.ShowElapsed = true
+	}
+
+	if options.FinalMSG != "" {
+		s.FinalMSG = options.FinalMSG
+	}
+
+	if options.Timeout > 0 {
+		s.Timeout = options.Timeout
+	}
+
+	if options.ResultLog != nil {
+		s.ResultLog = options.ResultLog
+	}
+
+	if options.DebugWriter != nil {
+		s.DebugWriter = options.DebugWriter
+	}
+
+	if options.HandleSuspend {
+		s.HandleSuspend = true
+	}
+
+	if options.MinDisplayDuration != 0 {
+		s.MinDisplayDuration = options.MinDisplayDuration
+	}
+
+	if options.CaptureFrames != 0 {
+		s.CaptureFrames = options.CaptureFrames
+	}
+
+	if options.TimeBasedFrames {
+		s.TimeBasedFrames = true
+	}
+
+	if options.DebugLog != nil {
+		s.DebugLog = options.DebugLog
+	}
+
+	if options.CallbackTimeout != 0 {
+		s.CallbackTimeout = options.CallbackTimeout
+	}
+
+	if options.PersistWithoutErase {
+		s.PersistWithoutErase = true
+	}
+
+	if options.Coordinated {
+		s.Coordinated = true
+	}
+
+	if options.ProgressFD != nil {
+		s.ProgressFD = options.ProgressFD
+	}
+
+	if options.ForceTerminal {
+		s.ForceTerminal = true
+	}
+
+	if options.EnableNonTTY {
+		s.EnableNonTTY = true
+	}
+
+	if options.DisableGitHubActions {
+		s.githubActions = false
+	}
+
+	if options.Managed {
+		s.Managed = true
+	}
+
+	if options.AnimatedSegment != AnimateGlyph {
+		s.AnimatedSegment = options.AnimatedSegment
+	}
+
+	if options.EventOverflow != Drop {
+		s.EventOverflow = options.EventOverflow
+	}
+
+	if options.ProgressBar {
+		s.ProgressBar = true
+	}
+
+	if options.BarWidth > 0 {
+		s.BarWidth = options.BarWidth
+	}
+
+	if options.BarWidthAuto {
+		s.BarWidthAuto = true
+	}
+
+	if options.TrackFocus {
+		s.TrackFocus = true
+	}
+
+	if options.FocusReader != nil {
+		s.FocusReader = options.FocusReader
+	}
+
+	if len(options.TextAttributes) > 0 {
+		s.TextAttributes(options.TextAttributes...)
+	}
+
+	if options.SymbolColor != "" {
+		s.SetSymbolColor(options.SymbolColor)
+	}
+
+	if options.PrefixColor != "" {
+		s.SetPrefixColor(options.PrefixColor)
+	}
+
+	if options.TextColor != "" {
+		s.SetTextColor(options.TextColor)
+	}
+
+	if options.Template != "" {
+		s.SetTemplate(options.Template)
+	}
+
+	if options.JSONOutput {
+		s.JSONOutput = true
+	}
+
+	if options.PersistBlink > 0 {
+		s.PersistBlink = options.PersistBlink
+	}
+
+	if options.HandleInterrupt {
+		s.HandleInterrupt = true
+	}
+
+	if options.MinInterval > 0 {
+		s.MinInterval = options.MinInterval
+	}
+
+	if options.RTL {
+		s.RTL = true
+	}
+
+	if options.ShowBytes {
+		s.showBytes = true
+	}
+
+	if options.SuccessSymbol != "" {
+		s.SetSuccessSymbol(options.SuccessSymbol)
+	}
+
+	if options.FailureSymbol != "" {
+		s.SetFailureSymbol(options.FailureSymbol)
+	}
+
+	if options.WarningSymbol != "" {
+		s.SetWarningSymbol(options.WarningSymbol)
+	}
+
+	if options.InfoSymbol != "" {
+		s.SetInfoSymbol(options.InfoSymbol)
+	}
+
 	return s
 }
 
@@ -122,147 +455,362 @@ type Options struct {
 	Symbol                string
 	PrefixText            string
 	CharacterSet          []string
+	CharacterSetName      string
+	DisableColor          bool
+	FPS                   float64
+	FinalMSG              string
+	Timeout               time.Duration
 	Writer                io.Writer
 	Delay                 time.Duration
 	Padding               int
+	Indent                int
 	DisableElaspedSeconds bool
+	ShowElapsed           bool
+	ResultLog             io.Writer
+	DebugWriter           io.Writer
+	TextAttributes        []string
+	SymbolColor           string
+	PrefixColor           string
+	TextColor             string
+	Template              string
+	JSONOutput            bool
+	SuccessSymbol         string
+	FailureSymbol         string
+	WarningSymbol         string
+	InfoSymbol            string
+	HandleSuspend         bool
+	MinDisplayDuration    time.Duration
+	CaptureFrames         int
+	TimeBasedFrames       bool
+	DebugLog              func(format string, args ...interface{})
+	RandomCharSet         bool
+	RandomSeed            int64
+	CallbackTimeout       time.Duration
+	PersistWithoutErase   bool
+	PersistBlink          int
+	HandleInterrupt       bool
+	MinInterval           time.Duration
+	RTL                   bool
+	ShowBytes             bool
+	Coordinated           bool
+	ProgressFD            *os.File
+	ForceTerminal         bool
+	EnableNonTTY          bool
+	Managed               bool
+	AnimatedSegment       AnimatedSegment
+	EventOverflow         OverflowPolicy
+	ProgressBar           bool
+	BarWidth              int
+	BarWidthAuto          bool
+	TrackFocus            bool
+	FocusReader           io.Reader
+	DisableGitHubActions  bool
 }
 
 // Starts the spinner
 func (s *Spinner) Start() {
+	s.start(nil)
+}
+
+// StartWithContext starts the spinner and stops it, restoring the cursor and
+// erasing the line, once the context is cancelled or times out. A Managed
+// spinner is stopped too.
+func (s *Spinner) StartWithContext(ctx context.Context) {
+	s.start(ctx.Done())
+}
+
+// start starts the spinner, which stops by itself once done is closed.
+func (s *Spinner) start(done <-chan struct{}) {
+	s.waitForEvents()
+	if !s.acquireWriter() {
+		return
+	}
+
 	s.mu.Lock()
-	if s.active || !isRunningInTerminal() {
+	if s.JSONOutput {
+		s.startJSON()
+		s.mu.Unlock()
+		return
+	}
+	if s.githubActions {
+		s.startGroup()
+		s.mu.Unlock()
+		return
+	}
+	if s.active || !(s.ForceTerminal || isRunningInTerminal(s.Writer)) {
+		s.debugf("start: skipped, active=%t", s.active)
+		if !s.active {
+			if s.EnableNonTTY {
+				// leave a breadcrumb of the step in place of the animation
+				fmt.Fprint(s.out(), s.staticLine())
+			}
+			s.releaseWriter()
+		}
 		s.mu.Unlock()
 		return
 	}
-	if s.HideCursor && !isWindowsTerminalOnWindows {
+	if s.hidesCursor() {
 		// hides the cursor
-		fmt.Fprint(s.Writer, "\033[?25l")
+		if _, err := fmt.Fprint(s.out(), "\033[?25l"); s.startFailed(err) {
+			s.releaseWriter()
+			s.mu.Unlock()
+			return
+		}
 	}
+	s.debugf("start: cursor hidden=%t", s.hidesCursor())
 	s.active = true
+	s.err = nil
+	s.startTime = time.Now()
+	s.firstFrame = time.Time{}
+	s.lastFrame = time.Time{}
+	s.framesDrawn = 0
+	s.termWidth = terminalWidth(s.Writer)
+	s.sigChan = make(chan os.Signal, 1)
+	s.notifySignals(s.sigChan)
+	go s.watchSignals(s.sigChan)
+	s.startFocusTracking()
+	s.step = 0
+	s.paused = false
+	s.stopChan = make(chan struct{})
+	stopChan := s.stopChan
+	timeout := s.Timeout
+	s.emit(EventStarted, 0, s.Text)
+	if s.Managed {
+		s.debugf("start: managed, frames are rendered by RenderNextFrame")
+		s.mu.Unlock()
+		if done != nil {
+			go func() {
+				select {
+				case <-stopChan:
+				case <-done:
+					s.debugf("managed spinner stopping: context done")
+					s.stopIf(stopChan, true, true)
+				}
+			}()
+		}
+		return
+	}
 	s.mu.Unlock()
 
 	go func() {
-		for {
-			s.secondsElasped += 1
-			time.Sleep(time.Second * 1)
+		s.debugf("animation goroutine started")
+		// frames are scheduled by a ticker instead of sleeping after each
+		// one, so the time spent rendering doesn't add up; ticks missed
+		// while behind are dropped rather than caught up on
+		var ticker *time.Ticker
+		var interval time.Duration
+		defer func() {
+			if ticker != nil {
+				ticker.Stop()
+			}
+		}()
+		var timedOut <-chan time.Time
+		if timeout > 0 {
+			timer := time.NewTimer(timeout)
+			defer timer.Stop()
+			timedOut = timer.C
 		}
-	}()
-
-	go func() {
 		for {
-			for i := 0; i < len(s.chars); i++ {
-				select {
-				case <-s.stopChan:
+			select {
+			case <-stopChan:
+				s.debugf("animation goroutine stopped: stopChan closed")
+				return
+			case <-done:
+				s.debugf("animation goroutine stopping: context done")
+				s.stopIf(stopChan, true, true)
+				return
+			default:
+				s.waitForEvents()
+				s.mu.Lock()
+				// a stop, and maybe a start, may have happened while
+				// waiting for the lock, leaving the spinner to a newer
+				// goroutine
+				if !s.active || s.stopChan != stopChan {
+					s.debugf("animation goroutine stopped: spinner inactive")
+					s.mu.Unlock()
 					return
-				default:
-					s.mu.Lock()
-					if !s.active {
-						s.mu.Unlock()
-						return
-					}
-					if !isWindowsTerminalOnWindows {
-						s.erase()
-					}
-
-					if s.PreUpdate != nil {
-						s.PreUpdate(s)
-					}
-
-					var fullSymbol string
-					if s.Symbol != "" {
-						fullSymbol = s.Symbol + " "
-					} else {
-						fullSymbol = ""
-					}
-
-					var fullPrefixText string
-					if s.PrefixText != "" {
-						fullPrefixText = s.PrefixText + " "
-					} else {
-						fullPrefixText = ""
-					}
-
-					var fullText string
-					if s.Text != "" {
-						fullText = " " + s.Text
-					} else {
-						fullText = ""
-					}
+				}
+				if !s.paused {
+					s.renderNextFrame()
+				}
+				delay := s.Delay
+				s.mu.Unlock()
 
-					var charStyled string
-					if runtime.GOOS == "windows" {
-						if s.Writer == os.Stderr {
-							charStyled = s.chars[i]
-						} else {
-							charStyled = s.color(s.chars[i])
-						}
-					} else {
-						charStyled = s.color(s.chars[i])
+				if delay <= 0 {
+					delay = minDelay
+				}
+				if delay != interval {
+					if ticker != nil {
+						ticker.Stop()
 					}
+					ticker = time.NewTicker(delay)
+					interval = delay
+				}
+				select {
+				case <-ticker.C:
+				case <-stopChan:
+					s.debugf("animation goroutine stopped: stopChan closed")
+					return
+				case <-done:
+					// handled at the top of the loop
+				case <-timedOut:
+					s.debugf("animation goroutine stopping: timed out after %s", timeout)
+					s.timeOut(stopChan)
+					return
+				}
+			}
+		}
+	}()
+}
 
-					var padding string
-					if s.Padding > 0 {
-						padding = strings.Repeat(" ", s.Padding)
-					}
+// RenderNextFrame advances a running spinner to its next frame and writes it
+// out. It's how a Managed spinner is animated from an external render loop.
+func (s *Spinner) RenderNextFrame() {
+	s.waitForEvents()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.active || s.paused || s.static() {
+		return
+	}
+	s.renderNextFrame()
+}
 
-					var elaspedSeconds string
+// Pause freezes the animation, leaving the current frame on screen, until
+// Unpause is called. The spinner is still active while it's paused.
+func (s *Spinner) Pause() {
+	s.mu.Lock()
+	s.paused = true
+	s.mu.Unlock()
+}
 
-					if s.ShowElaspedSeconds {
-						elaspedSeconds = color.New(color.FgHiBlack).SprintFunc()(" [" + strconv.Itoa(s.secondsElasped) + "s]")
-					} else {
-						elaspedSeconds = ""
-					}
+// Unpause carries on with the animation from the frame it was paused on.
+func (s *Spinner) Unpause() {
+	s.mu.Lock()
+	s.paused = false
+	s.mu.Unlock()
+}
 
-					outColor := fmt.Sprintf("\r%s%s%s%s%s%s", padding, fullSymbol, fullPrefixText, charStyled, fullText, elaspedSeconds)
-					outPlain := fmt.Sprintf("\r%s%s%s%s%s%v", padding, fullSymbol, fullPrefixText, s.chars[i], fullText, s.secondsElasped)
+// Frame returns the current frame, styling included, without writing it out
+// and without the carriage return it's drawn with, for embedding it in other
+// output.
+func (s *Spinner) Frame() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	outColor, _ := s.compose()
+	return strings.TrimPrefix(outColor, "\r")
+}
 
-					fmt.Fprint(s.Writer, outColor)
-					s.lastOutput = outPlain
-					delay := s.Delay
+// CurrentFrame returns the index in the character set of the current frame
+// and its character. In PostUpdate callbacks it's the frame just drawn.
+func (s *Spinner) CurrentFrame() (int, string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.currentFrame()
+}
 
-					if s.PostUpdate != nil {
-						s.PostUpdate(s)
-					}
+// currentFrame returns the index of the current frame and its character.
+// Caller must already hold s.lock.
+func (s *Spinner) currentFrame() (int, string) {
+	if s.frame < len(s.chars) {
+		return s.frame, s.charAt(s.frame)
+	}
+	return s.frame, ""
+}
 
-					s.mu.Unlock()
-					time.Sleep(delay)
-				}
-			}
+// renderNextFrame advances to the next frame and writes it out.
+// Caller must already hold s.lock.
+func (s *Spinner) renderNextFrame() {
+	if s.PreUpdate != nil {
+		s.runCallback("PreUpdate", s.PreUpdate)
+		if !s.active || s.paused {
+			// stopped or paused by the callback
+			return
 		}
-	}()
-}
+	}
+	s.advanceFrame()
+	if width := terminalWidth(s.Writer); width > 0 {
+		// follow the terminal as it's resized, on systems without SIGWINCH too
+		s.termWidth = width
+	}
 
-// Stops the spinner
-func (s *Spinner) Stop() {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	if s.active {
-		s.active = false
-		if s.HideCursor && !isWindowsTerminalOnWindows {
-			// makes the cursor visible
-			fmt.Fprint(s.Writer, "\033[?25h")
+	// only redraw when the line actually changed, and not more often
+	// than MinInterval allows, the frames advance meanwhile regardless
+	outColor, outPlain := s.compose()
+	now := time.Now()
+	if outColor != s.lastWritten && now.Sub(s.lastFrame) >= s.MinInterval {
+		if !isWindowsTerminalOnWindows {
+			s.erase()
 		}
-		s.erase()
+		if s.active {
+			s.writeFrame(outColor, outPlain)
+			// timed from when the frame was due rather than from when a
+			// slow writer took it, which would hold back the next one
+			s.lastFrame = now
+		}
+	}
 
-		s.stopChan <- struct{}{}
+	if s.PostUpdate != nil {
+		s.runCallback("PostUpdate", s.PostUpdate)
 	}
 }
 
-// Stops the spinner and prints out a symbol with text passed in as arguments
-func (s *Spinner) StopAndPersist(symbol string, text string) {
-	s.Stop()
+// advanceFrame moves on to the next frame.
+// Caller must already hold s.lock.
+func (s *Spinner) advanceFrame() {
+	// the character set may have been swapped for a shorter one
+	// since the last frame, so bound the index by its current length
+	if s.step >= len(s.chars) {
+		s.step = 0
+	}
+	if s.staticShown {
+		// the animation stands still while ShowStatic shows a character
+		s.reportProgress()
+		s.emitTextChange()
+		return
+	}
+	if s.TimeBasedFrames && s.Delay > 0 && len(s.chars) > 0 {
+		// derive the frame from the wall clock so all such spinners animate in lockstep
+		s.frame = int(time.Now().UnixNano() / int64(s.Delay) % int64(len(s.chars)))
+	} else {
+		s.frame = s.step
+	}
+	s.step++
+	s.reportProgress()
+	s.emitTextChange()
+}
 
-	var fullSymbol string
+// draw writes out the current frame.
+// Caller must already hold s.lock.
+func (s *Spinner) draw() {
+	outColor, outPlain := s.compose()
+	s.writeFrame(outColor, outPlain)
+}
+
+// compose builds the current frame, returning it with and without styling.
+// Caller must already hold s.lock.
+func (s *Spinner) compose() (string, string) {
+	var fullSymbol, symbolStyled string
 	if s.Symbol != "" {
-		fullSymbol = s.Symbol + " "
+		fullSymbol = sanitize(s.Symbol) + " "
+		symbolStyled = s.styleSymbol(sanitize(s.Symbol)) + " "
 	} else {
 		fullSymbol = ""
 	}
 
+	var fullPrefixText, prefixStyled string
+	if s.PrefixText != "" {
+		fullPrefixText = sanitize(s.PrefixText) + " "
+		prefixStyled = fullPrefixText
+		if s.prefixColor != nil {
+			prefixStyled = s.prefixColor(sanitize(s.PrefixText)) + " "
+		}
+	} else {
+		fullPrefixText = ""
+	}
+
 	var fullText string
-	if text != "" {
-		fullText = " " + text
+	if s.Text != "" {
+		fullText = " " + s.styleText(sanitize(s.Text))
 	} else {
 		fullText = ""
 	}
@@ -271,86 +819,1162 @@ func (s *Spinner) StopAndPersist(symbol string, text string) {
 	if s.Padding > 0 {
 		padding = strings.Repeat(" ", s.Padding)
 	}
+	if s.Indent > 0 {
+		padding = strings.Repeat(" ", s.Indent) + padding
+	}
 
-	fmt.Fprintf(s.Writer, "\r%s%s%s%s\n", padding, fullSymbol, symbol, fullText)
-}
+	secondsElasped := int(time.Since(s.startTime).Seconds())
 
-// Stops the spinner and prints out a success message.
-func (s *Spinner) Succeed(text string) {
-	s.StopAndPersist(logSymbols.SUCCESS, text)
-}
+	var elaspedSeconds string
 
-// Stops the spinner and prints out a failure message.
-func (s *Spinner) Fail(text string) {
-	s.StopAndPersist(logSymbols.ERROR, text)
-}
+	if s.ShowElaspedSeconds {
+		elaspedSeconds = color.New(color.FgHiBlack).SprintFunc()(" [" + strconv.Itoa(secondsElasped) + "s]")
+	} else {
+		elaspedSeconds = ""
+	}
 
-// Stops the spinner and prints out an info message.
-func (s *Spinner) Info(text string) {
-	s.StopAndPersist(logSymbols.INFO, text)
-}
+	suffix := s.suffix()
 
-// Stops the spinner and prints out a unicorn message.
-func (s *Spinner) Unicorn(text string) {
-	s.StopAndPersist("🦄", text)
-}
+	var animated, animatedStyled string
+	if s.frame < len(s.chars) {
+		animated = s.charAt(s.frame)
+		animatedStyled = s.styledFrame(s.frame)
+	} else {
+		animatedStyled = s.styleChar(animated)
+	}
+	if s.staticShown {
+		animated = sanitize(s.staticChar)
+		animatedStyled = s.styleChar(animated)
+	}
 
-// Restart will stop and start the indicator.
-func (s *Spinner) Restart() {
-	s.Stop()
-	s.Start()
-}
+	// the character set animates the segment asked for, while the
+	// character's own place holds the first character of the set
+	char, charStyled := animated, animatedStyled
+	prefixColor, prefixPlain := prefixStyled, fullPrefixText
+	suffixColor, suffixPlain := suffix, suffix
+	if s.AnimatedSegment != AnimateGlyph && len(s.chars) > 0 {
+		char = s.charAt(0)
+		charStyled = s.styledFrame(0)
+		switch s.AnimatedSegment {
+		case AnimatePrefix:
+			prefixColor = animatedStyled + " " + prefixStyled
+			prefixPlain = animated + " " + fullPrefixText
+		case AnimateSuffix:
+			suffixColor = suffix + " " + animatedStyled
+			suffixPlain = suffix + " " + animated
+		}
+	}
 
-// Reverse will reverse the order of the slice assigned to the indicator.
-func (s *Spinner) Reverse() {
-	s.mu.Lock()
-	for i, j := 0, len(s.chars)-1; i < j; i, j = i+1, j-1 {
-		s.chars[i], s.chars[j] = s.chars[j], s.chars[i]
+	if s.barShown() {
+		char = s.progressBar(displayWidth(padding + fullSymbol + prefixPlain + fullText + suffixPlain + elaspedSeconds))
+		charStyled = s.styleChar(char)
 	}
-	s.mu.Unlock()
-}
 
-// Color will set the struct field for the given color to be used. The spinner
-// will need to be explicitly restarted.
-func (s *Spinner) Color(colors ...string) error {
-	colorAttributes := make([]color.Attribute, len(colors))
+	if s.termWidth >= narrowWidth && s.Text != "" {
+		// cut the text short rather than wrap the line, which the erase
+		// couldn't clear, leaving the last column free
+		room := s.termWidth - 1 - displayWidth(padding+fullSymbol+prefixPlain+char+suffixPlain+elaspedSeconds) - 1
+		if text := sanitize(s.Text); displayWidth(text) > room {
+			text = truncate(text, room)
+			if text == "" {
+				fullText = ""
+			} else {
+				fullText = " " + s.styleText(text)
+			}
+		}
+	}
 
-	// Verify colours are valid and place the appropriate attribute in the array
-	for index, c := range colors {
-		if !validColor(c) {
-			return errInvalidColor
+	outColor := fmt.Sprintf("\r%s%s%s%s%s%s%s", padding, symbolStyled, prefixColor, charStyled, fullText, suffixColor, elaspedSeconds)
+	// the plain line is measured to erase it, so it holds everything
+	// shown, wide characters and the elapsed seconds included
+	outPlain := fmt.Sprintf("\r%s%s%s%s%s%s%s", padding, fullSymbol, prefixPlain, char, stripANSI(fullText), suffixPlain, stripANSI(elaspedSeconds))
+	if s.RTL {
+		outColor = "\r" + padding + rtlLine(fullText+suffixColor+elaspedSeconds, charStyled, prefixColor, symbolStyled)
+		outPlain = "\r" + padding + rtlLine(stripANSI(fullText)+suffixPlain+stripANSI(elaspedSeconds), char, prefixPlain, fullSymbol)
+	}
+	if s.template != nil {
+		elapsed := s.elapsedString()
+		styled := frameFields{
+			Symbol:  strings.TrimSuffix(symbolStyled, " "),
+			Prefix:  strings.TrimSuffix(prefixColor, " "),
+			Char:    charStyled,
+			Text:    strings.TrimPrefix(fullText, " "),
+			Suffix:  strings.TrimPrefix(suffixColor, " "),
+			Elapsed: elapsed,
+		}
+		plain := frameFields{
+			Symbol:  strings.TrimSuffix(fullSymbol, " "),
+			Prefix:  strings.TrimSuffix(prefixPlain, " "),
+			Char:    char,
+			Text:    stripANSI(styled.Text),
+			Suffix:  strings.TrimPrefix(suffixPlain, " "),
+			Elapsed: elapsed,
+		}
+		if lineColor, linePlain, ok := s.executeTemplate(styled, plain); ok {
+			outColor = "\r" + padding + lineColor
+			outPlain = "\r" + padding + stripANSI(linePlain)
+		}
+	}
+	if s.narrow() {
+		// there's no room for more than the character on a tiny
+		// terminal, and not even for that if it's too wide
+		if displayWidth(animated) > s.termWidth {
+			animated, animatedStyled = "", ""
 		}
-		colorAttributes[index] = colorAttributeMap[c]
+		outColor = "\r" + animatedStyled
+		outPlain = "\r" + animated
 	}
 
-	s.mu.Lock()
-	s.color = color.New(colorAttributes...).SprintFunc()
-	s.mu.Unlock()
-	return nil
+	if s.noColor {
+		outColor = stripANSI(outColor)
+	} else if !color.NoColor {
+		// make sure no color leaks into later output, even if the process dies mid-frame
+		outColor += resetSequence
+	}
+
+	return outColor, outPlain
 }
 
-// UpdateSpeed will set the indicator delay to the given value.
-func (s *Spinner) UpdateSpeed(d time.Duration) {
+// styleChar styles the character, or whatever is shown in its place, with the
+// color of the current frame.
+// Caller must already hold s.lock.
+func (s *Spinner) styleChar(char string) string {
+	if runtime.GOOS == "windows" && s.Writer == os.Stderr {
+		return char
+	}
+	return s.frameColor()(char)
+}
+
+// styledChar is a character of the character set along with the character
+// styled with the spinner color.
+type styledChar struct {
+	char   string
+	styled string
+}
+
+// styledFrame returns the character at the given index of the character set
+// styled like styleChar does. With a single color the styled characters are
+// kept in styledChars, so frames are styled once rather than on every tick.
+// Caller must already hold s.lock.
+func (s *Spinner) styledFrame(i int) string {
+	char := s.charAt(i)
+	if s.unfocused || s.colorCond != nil || len(s.palette) > 0 || runtime.GOOS == "windows" && s.Writer == os.Stderr {
+		return s.styleChar(char)
+	}
+	if len(s.styledChars) != len(s.chars) {
+		s.styledChars = make([]styledChar, len(s.chars))
+	}
+	// the character is compared too, so a character set changed in place
+	// is styled anew
+	if c := s.styledChars[i]; c.char == char && c.styled != "" {
+		return c.styled
+	}
+	s.styledChars[i] = styledChar{char: char, styled: s.color(char)}
+	return s.styledChars[i].styled
+}
+
+// hidesCursor reports whether the cursor is hidden while the spinner runs.
+// Caller must already hold s.lock.
+func (s *Spinner) hidesCursor() bool {
+	return s.HideCursor && !isWindowsTerminalOnWindows && !s.noColor
+}
+
+// narrow reports whether the terminal is too narrow to show more than the
+// character.
+// Caller must already hold s.lock.
+func (s *Spinner) narrow() bool {
+	return s.termWidth > 0 && s.termWidth < narrowWidth
+}
+
+// writeFrame writes out a composed frame.
+// Caller must already hold s.lock.
+func (s *Spinner) writeFrame(outColor string, outPlain string) {
+	s.debugf("writing frame %d under lock", s.frame)
+	if _, err := fmt.Fprint(s.out(), outColor); err != nil {
+		s.writeFailed(err)
+	}
+	s.lastWritten = outColor
+	s.lastOutput = outPlain
+	s.emit(EventFrameRendered, s.frame, "")
+	s.captureFrame(outPlain)
+	s.framesDrawn++
+	s.lastFrame = time.Now()
+	if s.firstFrame.IsZero() {
+		s.firstFrame = s.lastFrame
+	}
+}
+
+// suffix composes the extra information shown after the text.
+// Caller must already hold s.lock.
+func (s *Spinner) suffix() string {
+	return s.bytesSuffix() + s.progressSuffix() + s.rateSuffix() + s.queueSuffix() + s.elapsedSuffix()
+}
+
+// SetShowElapsed sets whether the time since the spinner started is shown
+// after the text.
+func (s *Spinner) SetShowElapsed(show bool) {
+	s.mu.Lock()
+	s.ShowElapsed = show
+	s.mu.Unlock()
+}
+
+// elapsedSuffix returns the time since the spinner started to show after the
+// text, if asked for. It's shown to a tenth of a second for the first minute
+// and to the second after that.
+// Caller must already hold s.lock.
+func (s *Spinner) elapsedSuffix() string {
+	if !s.ShowElapsed || s.startTime.IsZero() {
+		return ""
+	}
+	return " (" + s.elapsedString() + ")"
+}
+
+// elapsedString returns the time since the spinner started, to a tenth of a
+// second for the first minute and to the second after that.
+// Caller must already hold s.lock.
+func (s *Spinner) elapsedString() string {
+	if s.startTime.IsZero() {
+		return ""
+	}
+	elapsed := time.Since(s.startTime)
+	if elapsed < time.Minute {
+		elapsed = elapsed.Round(100 * time.Millisecond)
+	} else {
+		elapsed = elapsed.Round(time.Second)
+	}
+	return elapsed.String()
+}
+
+// frameColor returns the function styling the character of the current frame.
+// Caller must already hold s.lock.
+func (s *Spinner) frameColor() func(a ...interface{}) string {
+	if s.unfocused {
+		return dimColor
+	}
+	if s.colorCond != nil {
+		if s.colorCond() {
+			return s.colorWhenTrue
+		}
+		return s.colorWhenFalse
+	}
+	if len(s.palette) > 0 {
+		// counted by frames drawn rather than by character, so the colors
+		// keep alternating whatever the length of the character set
+		return s.palette[s.framesDrawn%len(s.palette)]
+	}
+	return s.color
+}
+
+// Stops the spinner. It's safe to call any number of times, before Start or
+// after the spinner stopped already, doing nothing then.
+func (s *Spinner) Stop() {
+	s.stop(true, true)
+}
+
+// Stats summarizes a run of the spinner.
+type Stats struct {
+	Elapsed time.Duration // Elapsed is how long the spinner ran
+	Frames  int           // Frames is the number of frames drawn
+}
+
+// StopWithStats stops the spinner and returns how long it ran and how many
+// frames it drew. The stats are zero if the spinner wasn't running.
+func (s *Spinner) StopWithStats() Stats {
+	s.mu.RLock()
+	active := s.active
+	started := s.startTime
+	s.mu.RUnlock()
+	if !active {
+		return Stats{}
+	}
+
+	s.stop(true, true)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return Stats{Elapsed: time.Since(started), Frames: s.framesDrawn}
+}
+
+// Elapsed returns how long the spinner has been running since it was last
+// started, or restarted, and 0 when it isn't running.
+func (s *Spinner) Elapsed() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !s.active {
+		return 0
+	}
+	return time.Since(s.startTime)
+}
+
+// stop stops the spinner, erasing the current frame if asked to and then
+// writing out FinalMSG, if set, if asked to.
+func (s *Spinner) stop(erase bool, withFinalMSG bool) {
+	s.waitToStop()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopLocked(erase, withFinalMSG)
+}
+
+// stopIf stops the spinner like stop does, unless it was stopped, and maybe
+// started again, since the run of stopChan started. The check and the stop
+// happen under one lock, so a newer run is never stopped in its place.
+func (s *Spinner) stopIf(stopChan chan struct{}, erase bool, withFinalMSG bool) {
+	s.waitToStop()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopChan == stopChan {
+		s.stopLocked(erase, withFinalMSG)
+	}
+}
+
+// waitToStop waits for the spinner to have been on screen for the minimum
+// display duration, and for room for the events of the stop.
+// Caller must not hold s.lock.
+func (s *Spinner) waitToStop() {
+	s.waitForEvents()
+	s.mu.RLock()
+	var wait time.Duration
+	if s.active && !s.firstFrame.IsZero() {
+		wait = s.MinDisplayDuration - time.Since(s.firstFrame)
+	}
+	s.mu.RUnlock()
+	if wait > 0 {
+		// keep the spinner on screen for the minimum display duration
+		time.Sleep(wait)
+	}
+}
+
+// stopLocked stops the spinner, see stop.
+// Caller must already hold s.lock.
+func (s *Spinner) stopLocked(erase bool, withFinalMSG bool) {
+	if s.active && s.JSONOutput {
+		s.active = false
+		// persisting writes out its own stop event
+		if withFinalMSG {
+			s.writeJSON(jsonEvent{Event: "stop", Status: "stopped", Text: s.FinalMSG})
+		}
+		s.emit(EventStopped, s.frame, "")
+		s.releaseWriter()
+		return
+	}
+	if s.active && s.githubActions {
+		if withFinalMSG && s.FinalMSG != "" {
+			fmt.Fprint(s.out(), sanitize(s.FinalMSG)+"\n")
+		}
+		s.endGroup()
+		s.releaseWriter()
+		return
+	}
+	if s.active {
+		s.active = false
+		defer s.releaseWriter()
+		if s.hidesCursor() {
+			// makes the cursor visible
+			fmt.Fprint(s.out(), "\033[?25h")
+		}
+		if erase {
+			s.erase()
+		}
+		if withFinalMSG && s.FinalMSG != "" {
+			fmt.Fprint(s.out(), sanitize(s.FinalMSG)+"\n")
+		}
+		stopSignals(s.sigChan)
+		s.stopFocusTracking()
+		s.emit(EventStopped, s.frame, "")
+
+		// closing never blocks, whether or not an animation goroutine
+		// is still around to notice, and only happens once per start
+		// since the spinner is no longer active
+		close(s.stopChan)
+		s.debugf("stop: closed stopChan")
+	}
+}
+
+// Stops the spinner and prints out a symbol with text passed in as arguments
+func (s *Spinner) StopAndPersist(symbol string, text string) {
+	s.persist("persist", symbol, text)
+}
+
+// StopAndPersistWithColor stops the spinner and prints out a symbol in the
+// given color followed by the text. Succeed and Fail print their symbols in
+// green and red like this.
+func (s *Spinner) StopAndPersistWithColor(symbol string, text string, c string) error {
+	colorFn, err := colorFunc(c)
+	if err != nil {
+		return err
+	}
+	s.persist("persist", colorFn(symbol), text)
+	return nil
+}
+
+// StopAndPersistLines stops the spinner and prints out a symbol with the first
+// of the lines, followed by the other lines aligned beneath its text.
+func (s *Spinner) StopAndPersistLines(symbol string, lines []string) {
+	if len(lines) == 0 {
+		s.persist("persist", symbol, "")
+		return
+	}
+	s.persist("persist", symbol, lines[0], lines[1:]...)
+}
+
+// persist stops the spinner, prints out the symbol and text, along with any
+// detail lines beneath it, and records the status in the result log if one is
+// set.
+func (s *Spinner) persist(status string, symbol string, text string, details ...string) {
+	s.waitToStop()
+	s.mu.Lock()
+	text, elapsed := s.persistLocked(status, symbol, text, details)
+	s.mu.Unlock()
+
+	if s.ResultLog != nil {
+		s.writeResult(status, text, elapsed)
+	}
+}
+
+// persistLocked stops the spinner and prints out the symbol with the text and
+// details, see persist. It returns the text persisted along with how long the
+// spinner ran.
+// Caller must already hold s.lock.
+func (s *Spinner) persistLocked(status string, symbol string, text string, details []string) (string, time.Duration) {
+	var elapsed time.Duration
+	if s.active {
+		elapsed = time.Since(s.startTime)
+	}
+	s.stopLocked(!s.PersistWithoutErase, false)
+
+	line := text
+	if len(details) > 0 {
+		text = strings.Join(append([]string{text}, details...), "\n")
+	}
+	if s.JSONOutput {
+		s.writeJSON(jsonEvent{Event: "stop", Status: status, Text: text})
+	} else if s.blinks() {
+		s.blink(symbol, line)
+		fmt.Fprint(s.out(), "\n"+s.detailLines(symbol, details))
+	} else {
+		fmt.Fprint(s.out(), s.persistedLine(symbol, line)+s.detailLines(symbol, details))
+	}
+	s.emit(EventPersisted, s.frame, text)
+	return text, elapsed
+}
+
+// PersistLine prints out a symbol with text above the spinner without stopping
+// it. Concurrent calls are serialized so each line is written out whole.
+func (s *Spinner) PersistLine(symbol string, text string) {
+	s.waitForEvents()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.debugf("persist line: %q", text)
+	s.emit(EventPersisted, s.frame, text)
+	if s.JSONOutput {
+		s.writeJSON(jsonEvent{Event: "persist", Text: text})
+		return
+	}
+	if !s.active || s.static() {
+		fmt.Fprint(s.out(), s.persistedLine(symbol, text))
+		return
+	}
+	if !isWindowsTerminalOnWindows {
+		s.erase()
+	}
+	fmt.Fprint(s.out(), s.persistedLine(symbol, text))
+	s.draw()
+}
+
+// Clear erases the line of a running spinner without stopping it, unlike Stop.
+// The spinner is drawn again on its next frame, or, for a Managed spinner, on
+// the next call to RenderNextFrame.
+func (s *Spinner) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.active || s.static() {
+		return
+	}
+	s.erase()
+}
+
+// WriteAbove writes out a line of output above the spinner, which is redrawn
+// beneath it, so logs scroll by above a spinner pinned to the bottom. If the
+// spinner isn't running the line is just written out.
+func (s *Spinner) WriteAbove(msg string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.active || s.static() {
+		fmt.Fprint(s.out(), msg+"\n")
+		return
+	}
+	if !isWindowsTerminalOnWindows {
+		s.erase()
+	}
+	fmt.Fprint(s.out(), "\r"+msg+"\n")
+	s.draw()
+}
+
+// staticLine composes the line written once by Start in place of the animation
+// when EnableNonTTY is set and Writer isn't a terminal: the symbol, prefix text
+// and text, uncolored.
+// Caller must already hold s.lock.
+func (s *Spinner) staticLine() string {
+	var parts []string
+	for _, part := range []string{s.Symbol, s.PrefixText, s.Text} {
+		if part != "" {
+			parts = append(parts, sanitize(part))
+		}
+	}
+
+	var padding string
+	if s.Padding > 0 {
+		padding = strings.Repeat(" ", s.Padding)
+	}
+	if s.Indent > 0 {
+		padding = strings.Repeat(" ", s.Indent) + padding
+	}
+	return stripANSI(padding+strings.Join(parts, " ")) + "\n"
+}
+
+// nonTTY reports whether Writer gets plain lines because EnableNonTTY is set
+// and it isn't a terminal.
+// Caller must already hold s.lock.
+func (s *Spinner) nonTTY() bool {
+	return s.EnableNonTTY && !s.ForceTerminal && !isRunningInTerminal(s.Writer)
+}
+
+// persistedLine composes a line with the given symbol and text.
+// Caller must already hold s.lock.
+func (s *Spinner) persistedLine(symbol string, text string) string {
+	var fullSymbol string
+	if s.Symbol != "" {
+		fullSymbol = s.styleSymbol(sanitize(s.Symbol)) + " "
+	} else {
+		fullSymbol = ""
+	}
+
+	var fullText string
+	if text != "" {
+		fullText = " " + sanitize(text)
+	} else {
+		fullText = ""
+	}
+
+	var padding string
+	if s.Padding > 0 {
+		padding = strings.Repeat(" ", s.Padding)
+	}
+	if s.Indent > 0 {
+		padding = strings.Repeat(" ", s.Indent) + padding
+	}
+
+	var reset string
+	if !color.NoColor && !s.noColor {
+		reset = resetSequence
+	}
+
+	var line string
+	if s.githubActions || s.PersistWithoutErase || s.nonTTY() {
+		line = fmt.Sprintf("%s%s%s%s%s\n", padding, fullSymbol, sanitize(symbol), fullText, reset)
+	} else {
+		line = fmt.Sprintf("\r%s%s%s%s%s\n", padding, fullSymbol, sanitize(symbol), fullText, reset)
+	}
+	if s.noColor {
+		return stripANSI(line)
+	}
+	return line
+}
+
+// detailLines composes the lines printed beneath a persisted line, indented
+// to line up with its text.
+// Caller must already hold s.lock.
+func (s *Spinner) detailLines(symbol string, details []string) string {
+	if len(details) == 0 {
+		return ""
+	}
+	width := s.Indent + s.Padding + displayWidth(sanitize(symbol)) + 1
+	if s.Symbol != "" {
+		width += displayWidth(sanitize(s.Symbol)) + 1
+	}
+	indent := strings.Repeat(" ", width)
+
+	var b strings.Builder
+	for _, line := range details {
+		b.WriteString(indent + sanitize(line) + "\n")
+	}
+	return b.String()
+}
+
+// result is a single record written to the result log.
+type result struct {
+	Status  string  `json:"status"`
+	Text    string  `json:"text"`
+	Elapsed float64 `json:"elapsed"` // seconds
+}
+
+// writeResult appends a JSON line describing a persisted status to the result log.
+func (s *Spinner) writeResult(status string, text string, elapsed time.Duration) {
+	b, err := json.Marshal(result{Status: status, Text: text, Elapsed: elapsed.Seconds()})
+	if err != nil {
+		return
+	}
+	s.ResultLog.Write(append(b, '\n'))
+}
+
+// Stops the spinner and prints out a success message.
+func (s *Spinner) Succeed(text string) {
+	s.persist("success", s.statusSymbol("success", logSymbols.SUCCESS), text)
+}
+
+// Stops the spinner and prints out a failure message.
+func (s *Spinner) Fail(text string) {
+	s.persist("failure", s.statusSymbol("failure", logSymbols.ERROR), text)
+}
+
+// Stops the spinner and prints out a warning message.
+func (s *Spinner) Warn(text string) {
+	s.persist("warning", s.statusSymbol("warning", logSymbols.WARNING), text)
+}
+
+// Stops the spinner and prints out an info message.
+func (s *Spinner) Info(text string) {
+	s.persist("info", s.statusSymbol("info", logSymbols.INFO), text)
+}
+
+// Stops the spinner and prints out a unicorn message.
+func (s *Spinner) Unicorn(text string) {
+	s.persist("unicorn", "🦄", text)
+}
+
+// WaitFor starts the spinner, waits for the wait group to be done and then
+// stops the spinner with a success message.
+func (s *Spinner) WaitFor(wg *sync.WaitGroup, successText string) {
+	s.Start()
+	wg.Wait()
+	s.Succeed(successText)
+}
+
+// SetTimeout sets how long the spinner may run before it stops by itself,
+// persisting a failure sign with "timed out". It applies from the next start
+// on, 0 lets the spinner run as long as it takes.
+func (s *Spinner) SetTimeout(d time.Duration) {
+	s.mu.Lock()
+	s.Timeout = d
+	s.mu.Unlock()
+}
+
+// timeOut stops the spinner with a timeout message, unless it was stopped, and
+// maybe started again, since the run that timed out started.
+// The check and the stop happen under one lock, like in stopIf.
+func (s *Spinner) timeOut(stopChan chan struct{}) {
+	symbol := s.statusSymbol("failure", Symbols["failure"])
+	s.waitToStop()
+	s.mu.Lock()
+	if !s.active || s.stopChan != stopChan {
+		s.mu.Unlock()
+		return
+	}
+	text, elapsed := s.persistLocked("persist", symbol, "timed out", nil)
+	s.mu.Unlock()
+
+	if s.ResultLog != nil {
+		s.writeResult("persist", text, elapsed)
+	}
+}
+
+// SetFinalMSG sets the message written out, on a line of its own, once Stop
+// erased the spinner. An empty message leaves just the erased line.
+func (s *Spinner) SetFinalMSG(msg string) {
+	s.mu.Lock()
+	s.FinalMSG = msg
+	s.mu.Unlock()
+}
+
+// Active reports whether the spinner is running.
+func (s *Spinner) Active() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.active
+}
+
+// Restart will stop and start the indicator, without writing FinalMSG in
+// between.
+func (s *Spinner) Restart() {
+	s.stop(true, false)
+	s.Start()
+}
+
+// Reverse will flip the direction the character set is animated in, carrying
+// on from the frame shown. The character set itself is left untouched, so
+// calling it twice goes back to the original direction.
+// It's safe to call from PreUpdate and PostUpdate to flip the direction
+// mid-animation, as they run without holding the lock.
+func (s *Spinner) Reverse() {
+	s.mu.Lock()
+	s.reverseLocked()
+	s.mu.Unlock()
+}
+
+// reverseLocked flips the direction of the animation.
+// Caller must already hold s.lock.
+func (s *Spinner) reverseLocked() {
+	s.reversed = !s.reversed
+	if s.frame < len(s.chars) {
+		// keep showing the same character, the next ones come from
+		// the other direction
+		s.frame = len(s.chars) - 1 - s.frame
+		s.step = s.frame + 1
+	}
+}
+
+// Direction is the direction a character set is animated in.
+type Direction int
+
+const (
+	// Forward animates the character set from its first frame to its last
+	Forward Direction = iota
+	// Backward animates the character set from its last frame to its first
+	Backward
+)
+
+// Direction returns the direction the character set is animated in, which
+// Reverse flips.
+func (s *Spinner) Direction() Direction {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.reversed {
+		return Backward
+	}
+	return Forward
+}
+
+// charAt returns the character shown at the given position of the animation,
+// counted from the end of the character set while reversed.
+// Caller must already hold s.lock.
+func (s *Spinner) charAt(i int) string {
+	if s.reversed {
+		return s.chars[len(s.chars)-1-i]
+	}
+	return s.chars[i]
+}
+
+// Color will set the struct field for the given color to be used. The spinner
+// will need to be explicitly restarted.
+func (s *Spinner) Color(colors ...string) error {
+	colorFn, err := colorFunc(colors...)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.color = colorFn
+	s.styledChars = nil
+	s.palette = nil
+	s.mu.Unlock()
+	return nil
+}
+
+// DisableColor will render the spinner without any colors or other styling
+// and without hiding the cursor, leaving only the escape sequences needed to
+// redraw it. It's turned on by New when the NO_COLOR environment variable is
+// set to a value that isn't empty.
+func (s *Spinner) DisableColor() {
+	s.mu.Lock()
+	s.noColor = true
+	s.color = fmt.Sprint
+	s.styledChars = nil
+	s.mu.Unlock()
+}
+
+// TwoTone will color the spinner character with color a on even frames and
+// with color b on odd frames. Calling Color goes back to a single color.
+func (s *Spinner) TwoTone(a, b string) error {
+	return s.SetColors(a, b)
+}
+
+// SetColors will color the spinner character with the given colors in turn,
+// one per frame. Calling Color goes back to a single color.
+func (s *Spinner) SetColors(colors ...string) error {
+	palette := make([]func(a ...interface{}) string, len(colors))
+	for i, c := range colors {
+		if !validColor(c) {
+			return fmt.Errorf("color %q: %w", c, errInvalidColor)
+		}
+		palette[i] = color.New(colorAttributeMap[c]).SprintFunc()
+	}
+
+	s.mu.Lock()
+	s.palette = palette
+	s.mu.Unlock()
+	return nil
+}
+
+// SetConditionalColor will color the spinner with one color while the given
+// condition holds and with the other one otherwise. The condition is evaluated
+// on every frame.
+func (s *Spinner) SetConditionalColor(cond func() bool, whenTrue, whenFalse string) error {
+	trueFn, err := colorFunc(whenTrue)
+	if err != nil {
+		return err
+	}
+	falseFn, err := colorFunc(whenFalse)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.colorCond = cond
+	s.colorWhenTrue = trueFn
+	s.colorWhenFalse = falseFn
+	s.mu.Unlock()
+	return nil
+}
+
+// TextAttributes will set the attributes, like bold or underline, the text is
+// rendered with. The spinner character itself is left as is.
+func (s *Spinner) TextAttributes(attributes ...string) error {
+	textStyle, err := colorFunc(attributes...)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.textStyle = textStyle
+	s.mu.Unlock()
+	return nil
+}
+
+// SetSymbolColor will set the colors, or other attributes, Symbol is rendered
+// with. Without any Symbol is rendered plain.
+func (s *Spinner) SetSymbolColor(colors ...string) error {
+	colorFn, err := segmentColor(colors)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.symbolColor = colorFn
+	s.mu.Unlock()
+	return nil
+}
+
+// SetPrefixColor will set the colors, or other attributes, PrefixText is
+// rendered with. Without any PrefixText is rendered plain.
+func (s *Spinner) SetPrefixColor(colors ...string) error {
+	colorFn, err := segmentColor(colors)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.prefixColor = colorFn
+	s.mu.Unlock()
+	return nil
+}
+
+// SetTextColor will set the colors, or other attributes, Text is rendered
+// with, on top of the TextAttributes. Without any Text is rendered plain.
+func (s *Spinner) SetTextColor(colors ...string) error {
+	colorFn, err := segmentColor(colors)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.textColor = colorFn
+	s.mu.Unlock()
+	return nil
+}
+
+// segmentColor returns the function coloring a segment of the line with the
+// given colors, nil for none.
+func segmentColor(colors []string) (func(a ...interface{}) string, error) {
+	if len(colors) == 0 {
+		return nil, nil
+	}
+	return colorFunc(colors...)
+}
+
+// styleSymbol styles Symbol with SymbolColor, if set.
+// Caller must already hold s.lock.
+func (s *Spinner) styleSymbol(symbol string) string {
+	if s.symbolColor != nil {
+		return s.symbolColor(symbol)
+	}
+	return symbol
+}
+
+// styleText styles Text with TextColor and TextAttributes, if set.
+// Caller must already hold s.lock.
+func (s *Spinner) styleText(text string) string {
+	if s.textColor != nil {
+		text = s.textColor(text)
+	}
+	if s.textStyle != nil {
+		text = s.textStyle(text)
+	}
+	return text
+}
+
+// UpdateSpeed will set the indicator delay to the given value.
+func (s *Spinner) UpdateSpeed(d time.Duration) {
 	s.mu.Lock()
 	s.Delay = d
+	s.explicitDelay = true
+	s.cycleDuration = 0
+	s.mu.Unlock()
+}
+
+// errInvalidFPS is returned when a frame rate isn't a positive number
+var errInvalidFPS = errors.New("frames per second must be positive")
+
+// UpdateFPS will set the indicator delay so the spinner shows the given number
+// of frames per second.
+func (s *Spinner) UpdateFPS(fps float64) error {
+	if !(fps > 0) || math.IsInf(fps, 1) {
+		return errInvalidFPS
+	}
+	s.UpdateSpeed(fpsDelay(fps))
+	return nil
+}
+
+// fpsDelay returns the delay between frames at the given frame rate.
+func fpsDelay(fps float64) time.Duration {
+	return time.Duration(float64(time.Second) / fps)
+}
+
+// SetCycleDuration will set the indicator delay so that one full pass over the
+// character set takes the given duration. The delay is recomputed when the
+// character set changes.
+func (s *Spinner) SetCycleDuration(d time.Duration) {
+	s.mu.Lock()
+	s.cycleDuration = d
+	s.applyCycleDuration()
 	s.mu.Unlock()
 }
 
+// applyCycleDuration sets the delay from the cycle duration, if there is one.
+// Caller must already hold s.lock.
+func (s *Spinner) applyCycleDuration() {
+	if s.cycleDuration > 0 && len(s.chars) > 0 {
+		s.Delay = s.cycleDuration / time.Duration(len(s.chars))
+	}
+}
+
 // UpdateCharSet will change the current character set to the given one.
 func (s *Spinner) UpdateCharSet(cs []string) {
 	s.mu.Lock()
 	s.chars = cs
+	if s.frame >= len(cs) {
+		s.frame = 0
+	}
+	s.applyRecommendedDelay()
+	s.applyCycleDuration()
 	s.mu.Unlock()
 }
 
+// UpdateCharSetChecked will change the current character set to the given one
+// like UpdateCharSet, unless it has no frames or a frame would break the line,
+// which is returned as an error instead.
+func (s *Spinner) UpdateCharSetChecked(cs []string) error {
+	if err := validateCharSet(cs); err != nil {
+		return err
+	}
+	s.UpdateCharSet(cs)
+	return nil
+}
+
+// SetText sets the text shown after the spinner. Use it, rather than writing
+// the Text field, to change the text while the spinner is running.
+func (s *Spinner) SetText(text string) {
+	s.mu.Lock()
+	if s.active && s.JSONOutput && text != s.Text {
+		s.writeJSON(jsonEvent{Event: "text", Text: text})
+	}
+	s.setText(text)
+	s.mu.Unlock()
+}
+
+// setText sets the text shown, or while a flashed message is shown, the text
+// restored once it expires.
+// Caller must already hold s.lock.
+func (s *Spinner) setText(text string) {
+	if s.flashing {
+		s.flashText = text
+		return
+	}
+	s.Text = text
+	s.reportProgress()
+}
+
+// SetPrefixText sets the prefix text shown before the spinner. Use it, rather
+// than writing the PrefixText field, while the spinner is running.
+func (s *Spinner) SetPrefixText(prefix string) {
+	s.mu.Lock()
+	s.PrefixText = prefix
+	s.mu.Unlock()
+}
+
+// SetSymbol sets the symbol shown before the prefix text. Use it, rather than
+// writing the Symbol field, while the spinner is running.
+func (s *Spinner) SetSymbol(symbol string) {
+	s.mu.Lock()
+	s.Symbol = symbol
+	s.mu.Unlock()
+}
+
+// WithColor sets the color of the spinner and returns it for chaining.
+// Invalid colors are ignored, use Color to get the error.
+func (s *Spinner) WithColor(colors ...string) *Spinner {
+	s.Color(colors...)
+	return s
+}
+
+// WithText sets the text shown after the spinner and returns it for chaining.
+func (s *Spinner) WithText(text string) *Spinner {
+	s.SetText(text)
+	return s
+}
+
+// WithDelay sets the delay of the spinner and returns it for chaining.
+func (s *Spinner) WithDelay(d time.Duration) *Spinner {
+	s.UpdateSpeed(d)
+	return s
+}
+
+// WithSymbol sets the symbol shown before the prefix text and returns the
+// spinner for chaining.
+func (s *Spinner) WithSymbol(symbol string) *Spinner {
+	s.SetSymbol(symbol)
+	return s
+}
+
+// WithPrefix sets the prefix text shown before the spinner and returns it for
+// chaining.
+func (s *Spinner) WithPrefix(prefix string) *Spinner {
+	s.SetPrefixText(prefix)
+	return s
+}
+
+// WithWriter sets the writer the spinner writes to and returns it for
+// chaining. A running spinner is erased from the old writer and carries on on
+// the new one, unless the new one isn't a terminal, in which case it stops.
+func (s *Spinner) WithWriter(w io.Writer) *Spinner {
+	s.mu.Lock()
+	if !s.active || s.static() {
+		s.Writer = w
+		s.mu.Unlock()
+		return s
+	}
+	if !(s.ForceTerminal || isRunningInTerminal(w)) {
+		s.mu.Unlock()
+		// stop on the old writer, restoring its cursor
+		s.stop(true, false)
+		s.mu.Lock()
+		s.Writer = w
+		s.mu.Unlock()
+		return s
+	}
+	defer s.mu.Unlock()
+
+	s.erase()
+	s.moveWriter(w)
+	s.draw()
+	return s
+}
+
+// moveWriter points the running spinner at w, moving the hidden cursor over
+// from the old writer and measuring the width of the new one.
+// Caller must already hold s.lock.
+func (s *Spinner) moveWriter(w io.Writer) {
+	hideCursor := s.hidesCursor()
+	if hideCursor {
+		// makes the cursor visible on the old writer
+		fmt.Fprint(s.out(), "\033[?25h")
+	}
+	s.Writer = w
+	if hideCursor {
+		// hides the cursor on the new writer
+		fmt.Fprint(s.out(), "\033[?25l")
+	}
+	s.termWidth = terminalWidth(w)
+}
+
+// Reconfigure applies the color, character set, delay, symbol, prefix text,
+// text and writer from the given options in one go and redraws the spinner if
+// it's running. Fields left at their zero value keep their current value.
+func (s *Spinner) Reconfigure(options Options) error {
+	var colorFn func(a ...interface{}) string
+	if options.Color != "" {
+		var err error
+		if colorFn, err = colorFunc(options.Color); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	if options.Writer != nil && s.active && !s.static() && !(s.ForceTerminal || isRunningInTerminal(options.Writer)) {
+		s.mu.Unlock()
+		// stop on the old writer, restoring its cursor, as WithWriter does
+		s.stop(true, false)
+		s.mu.Lock()
+	}
+	defer s.mu.Unlock()
+
+	if s.active && !s.static() {
+		s.erase()
+	}
+
+	if colorFn != nil {
+		s.color = colorFn
+		s.styledChars = nil
+		s.palette = nil
+	}
+
+	if len(options.CharacterSet) > 0 {
+		s.chars = options.CharacterSet
+		s.frame = 0
+		s.applyRecommendedDelay()
+		s.applyCycleDuration()
+	}
+
+	if options.Delay != 0 {
+		s.Delay = options.Delay
+		s.explicitDelay = true
+		s.cycleDuration = 0
+	}
+
+	if options.Symbol != "" {
+		s.Symbol = options.Symbol
+	}
+
+	if options.PrefixText != "" {
+		s.PrefixText = options.PrefixText
+	}
+
+	if options.Text != "" {
+		s.setText(options.Text)
+	}
+
+	if options.Writer != nil {
+		if s.active && !s.static() {
+			s.moveWriter(options.Writer)
+		} else {
+			s.Writer = options.Writer
+		}
+	}
+
+	if s.active && !s.static() {
+		s.draw()
+	}
+	return nil
+}
+
 // erase deletes written characters on the current line.
 // Caller must already hold s.lock.
 func (s *Spinner) erase() {
-	n := utf8.RuneCountInString(s.lastOutput)
+	n := displayWidth(s.lastOutput)
 	if runtime.GOOS == "windows" && !isWindowsTerminalOnWindows {
 		clearString := "\r" + strings.Repeat(" ", n) + "\r"
-		fmt.Fprint(s.Writer, clearString)
+		fmt.Fprint(s.out(), clearString)
 		s.lastOutput = ""
+		s.lastWritten = ""
 		return
 	}
 
@@ -360,8 +1984,29 @@ func (s *Spinner) erase() {
 	// cursor to the end of the line. If n is 1, clear from cursor to beginning
 	// of the line. If n is 2, clear entire line. Cursor position does not
 	// change.
-	fmt.Fprintf(s.Writer, "\r\033[K")
+	if _, err := fmt.Fprintf(s.out(), "\r\033[K"); err != nil {
+		s.writeFailed(err)
+	}
 	s.lastOutput = ""
+	s.lastWritten = ""
+}
+
+// out returns the writer to write output to, which copies it to DebugWriter
+// if one is set.
+// Caller must already hold s.lock.
+func (s *Spinner) out() io.Writer {
+	s.setWriteDeadline()
+	if s.DebugWriter == nil {
+		return s.Writer
+	}
+	return io.MultiWriter(s.Writer, s.DebugWriter)
+}
+
+// debugf passes a message about the spinner's internals to DebugLog, if set.
+func (s *Spinner) debugf(format string, args ...interface{}) {
+	if s.DebugLog != nil {
+		s.DebugLog(format, args...)
+	}
 }
 
 // Lock allows for manual control to lock the spinner.
@@ -384,7 +2029,11 @@ func GenerateNumberSequence(length int) []string {
 	return numSeq
 }
 
-// isRunningInTerminal check if stdout file descriptor is terminal
-func isRunningInTerminal() bool {
-	return isatty.IsTerminal(os.Stdout.Fd())
+// isRunningInTerminal check if the file descriptor behind the writer is terminal
+func isRunningInTerminal(w io.Writer) bool {
+	fd, ok := fileDescriptor(w)
+	if !ok {
+		return false
+	}
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
 }
@@ -0,0 +1,1088 @@
+package spintron
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	logSymbols "github.com/defaltd/log-symbols"
+)
+
+// syncBuffer is a bytes.Buffer safe for the animation goroutine to write to
+// while a test reads it.
+type syncBuffer struct {
+	mu sync.Mutex
+	b  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.String()
+}
+
+func (b *syncBuffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.b.Reset()
+}
+
+// cleanEnv unsets, for the duration of the test, the environment variables
+// changing how spinners write, so tests also pass in GitHub Actions.
+func cleanEnv(t testing.TB) {
+	t.Helper()
+	unsetenv(t, "GITHUB_ACTIONS")
+	unsetenv(t, "NO_COLOR")
+}
+
+// newTestSpinner returns a spinner writing to a buffer as if it were a
+// terminal, without the elapsed seconds and without holding back redraws, so
+// its output only depends on what the test does.
+func newTestSpinner(t *testing.T, options Options) (*Spinner, *syncBuffer) {
+	t.Helper()
+	cleanEnv(t)
+	buf := &syncBuffer{}
+	options.Writer = buf
+	options.ForceTerminal = true
+	s := New(options)
+	s.ShowElaspedSeconds = false
+	s.MinInterval = 0
+	return s, buf
+}
+
+// frames returns the lines drawn to the buffer, one per frame, without the
+// escape sequences around them.
+func frames(out string) []string {
+	var lines []string
+	for _, part := range strings.Split(out, "\r") {
+		part = stripANSI(part)
+		if part != "" && part != "\n" {
+			lines = append(lines, part)
+		}
+	}
+	return lines
+}
+
+func TestReconfigureColorClearsColors(t *testing.T) {
+	withColor(t)
+	s, buf := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"a", "b"}})
+	if err := s.SetColors("red", "green"); err != nil {
+		t.Fatal(err)
+	}
+	s.Start()
+	s.RenderNextFrame()
+	buf.Reset()
+	if err := s.Reconfigure(Options{Color: "blue"}); err != nil {
+		t.Fatal(err)
+	}
+	s.RenderNextFrame()
+	s.Stop()
+
+	out := buf.String()
+	if strings.Contains(out, "\x1b[31m") || strings.Contains(out, "\x1b[32m") {
+		t.Errorf("output %q still uses the colors set before", out)
+	}
+	if !strings.Contains(out, "\x1b[34m") {
+		t.Errorf("output %q isn't blue", out)
+	}
+}
+
+func TestReconfigureWriterMovesCursor(t *testing.T) {
+	s, old := newTestSpinner(t, Options{Managed: true})
+	s.Start()
+	s.RenderNextFrame()
+
+	w := &syncBuffer{}
+	if err := s.Reconfigure(Options{Writer: w, Text: "moved"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(old.String(), "\x1b[?25h") {
+		t.Errorf("old writer %q doesn't end by showing the cursor", old.String())
+	}
+	if !strings.HasPrefix(w.String(), "\x1b[?25l") || !strings.Contains(w.String(), "moved") {
+		t.Errorf("new writer %q doesn't hide the cursor and draw the spinner", w.String())
+	}
+	s.Stop()
+}
+
+func TestReconfigureWriterStopsOnNonTerminal(t *testing.T) {
+	s, old := newTestSpinner(t, Options{Managed: true})
+	s.Start()
+	s.RenderNextFrame()
+	s.mu.Lock()
+	s.ForceTerminal = false
+	s.mu.Unlock()
+
+	w := &syncBuffer{}
+	if err := s.Reconfigure(Options{Writer: w}); err != nil {
+		t.Fatal(err)
+	}
+	if s.Active() {
+		t.Error("spinner still runs after moving to a writer that isn't a terminal")
+	}
+	if w.String() != "" {
+		t.Errorf("spinner drew %q on a writer that isn't a terminal", w.String())
+	}
+	if !strings.Contains(old.String(), "\x1b[?25h") {
+		t.Errorf("old writer %q doesn't get its cursor back", old.String())
+	}
+}
+
+func TestRestartSkipsFinalMSG(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Managed: true, FinalMSG: "done\n"})
+	s.Start()
+	s.RenderNextFrame()
+	s.Restart()
+	if !s.Active() {
+		t.Error("spinner doesn't run after Restart")
+	}
+	if strings.Contains(buf.String(), "done") {
+		t.Errorf("Restart wrote FinalMSG: %q", buf.String())
+	}
+	s.Stop()
+	if n := strings.Count(buf.String(), "done"); n != 1 {
+		t.Errorf("FinalMSG written %d times, want once: %q", n, buf.String())
+	}
+}
+
+func TestIndent(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Managed: true, Indent: 4, CharacterSet: []string{"-"}, Text: "child"})
+	s.Start()
+	s.RenderNextFrame()
+	if got := stripANSI(lastFrame(s)); got != "    - child" {
+		t.Errorf("frame %q, want %q", got, "    - child")
+	}
+	s.StopAndPersist("*", "done")
+	if !strings.Contains(stripANSI(buf.String()), "    * done\n") {
+		t.Errorf("output %q doesn't persist an indented line", buf.String())
+	}
+}
+
+func TestReverseFromCallback(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"a", "b", "c", "d", "e"}})
+	n := 0
+	s.PostUpdate = func(s *Spinner) {
+		n++
+		if n%3 == 0 {
+			s.Reverse()
+		}
+	}
+
+	var got []string
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Start()
+		for i := 0; i < 9; i++ {
+			s.RenderNextFrame()
+			got = append(got, lastFrame(s))
+		}
+		s.Stop()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Reverse deadlocked in PostUpdate")
+	}
+
+	// every third frame the animation turns around on the character shown
+	want := "a b c b a e a b c"
+	if strings.Join(got, " ") != want {
+		t.Errorf("frames %q, want %q", strings.Join(got, " "), want)
+	}
+	if d := s.Direction(); d != Backward {
+		t.Errorf("direction %v after flipping three times, want %v", d, Backward)
+	}
+}
+
+func TestResultLog(t *testing.T) {
+	log := &syncBuffer{}
+	s, _ := newTestSpinner(t, Options{Managed: true, ResultLog: log})
+	s.Start()
+	s.Succeed("built")
+	s.Start()
+	s.Fail("tests failed")
+
+	lines := strings.Split(strings.TrimSuffix(log.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("result log %q, want two records", log.String())
+	}
+	for i, want := range []result{{Status: "success", Text: "built"}, {Status: "failure", Text: "tests failed"}} {
+		var got result
+		if err := json.Unmarshal([]byte(lines[i]), &got); err != nil {
+			t.Fatalf("record %q: %v", lines[i], err)
+		}
+		if got.Status != want.Status || got.Text != want.Text || got.Elapsed < 0 {
+			t.Errorf("record %+v, want %+v", got, want)
+		}
+	}
+}
+
+func TestPersistLineConcurrent(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Delay: time.Millisecond, CharacterSet: []string{"-"}})
+	s.Start()
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				s.PersistLine("*", fmt.Sprintf("line %d-%d", g, i))
+			}
+		}(g)
+	}
+	wg.Wait()
+	s.Stop()
+
+	// every line is written out whole on a line of its own, whatever was
+	// drawn before it on the same line was erased
+	seen := map[string]bool{}
+	for _, line := range strings.Split(stripANSI(buf.String()), "\n") {
+		if i := strings.LastIndex(line, "\r"); i >= 0 {
+			line = line[i+1:]
+		}
+		if strings.HasPrefix(line, "* ") {
+			seen[line] = true
+		}
+	}
+	for g := 0; g < 8; g++ {
+		for i := 0; i < 20; i++ {
+			if line := fmt.Sprintf("* line %d-%d", g, i); !seen[line] {
+				t.Fatalf("line %q isn't written out whole", line)
+			}
+		}
+	}
+	if len(seen) != 160 {
+		t.Errorf("%d distinct lines, want 160", len(seen))
+	}
+}
+
+func TestWaitFor(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Delay: time.Millisecond})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		wg.Done()
+	}()
+	s.WaitFor(&wg, "all done")
+
+	if s.Active() {
+		t.Error("spinner still runs after the wait group is done")
+	}
+	if n := strings.Count(buf.String(), "all done"); n != 1 {
+		t.Errorf("success message written %d times, want once: %q", n, buf.String())
+	}
+}
+
+func TestMinDisplayDuration(t *testing.T) {
+	const min = 50 * time.Millisecond
+	tests := []struct {
+		name    string
+		drawn   time.Duration // drawn is how long ago the first frame was drawn, negative for never
+		atLeast time.Duration
+		atMost  time.Duration
+	}{
+		{"just drawn", 0, min / 2, time.Second},
+		{"drawn long ago", time.Hour, 0, min / 2},
+		{"never drawn", -1, 0, min / 2},
+	}
+	for _, tt := range tests {
+		s, _ := newTestSpinner(t, Options{Managed: true})
+		s.MinDisplayDuration = min
+		s.Start()
+		if tt.drawn >= 0 {
+			s.RenderNextFrame()
+			s.mu.Lock()
+			s.firstFrame = time.Now().Add(-tt.drawn)
+			s.mu.Unlock()
+		}
+		began := time.Now()
+		s.Stop()
+		if took := time.Since(began); took < tt.atLeast || took > tt.atMost {
+			t.Errorf("%s: Stop took %v, want between %v and %v", tt.name, took, tt.atLeast, tt.atMost)
+		}
+	}
+}
+
+func TestWithChaining(t *testing.T) {
+	withColor(t)
+	w := &syncBuffer{}
+	s := New(Options{}).
+		WithColor("red").
+		WithText("chained").
+		WithDelay(250 * time.Millisecond).
+		WithSymbol(">").
+		WithPrefix("job").
+		WithWriter(w)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.Text != "chained" || s.Delay != 250*time.Millisecond || s.Symbol != ">" || s.PrefixText != "job" || s.Writer != w {
+		t.Errorf("chained options not all applied: text %q, delay %v, symbol %q, prefix %q", s.Text, s.Delay, s.Symbol, s.PrefixText)
+	}
+	if got := s.color("x"); got != "\x1b[31mx\x1b[0m" {
+		t.Errorf("color renders %q, want red", got)
+	}
+}
+
+func TestTimeBasedFrames(t *testing.T) {
+	cs := []string{"a", "b", "c", "d"}
+	first, _ := newTestSpinner(t, Options{Managed: true, CharacterSet: cs, Delay: time.Hour})
+	first.TimeBasedFrames = true
+	first.Start()
+	first.RenderNextFrame()
+	first.RenderNextFrame()
+	defer first.Stop()
+
+	time.Sleep(10 * time.Millisecond)
+	second, _ := newTestSpinner(t, Options{Managed: true, CharacterSet: cs, Delay: time.Hour})
+	second.TimeBasedFrames = true
+	second.Start()
+	second.RenderNextFrame()
+	defer second.Stop()
+
+	// the frame is picked by the wall clock, not counted from the start
+	want := cs[time.Now().UnixNano()/int64(time.Hour)%int64(len(cs))]
+	if a, b := first.LastOutput(), second.LastOutput(); a != want || b != want {
+		t.Errorf("frames %q and %q, want both %q", a, b, want)
+	}
+}
+
+func TestRedrawOnlyChangedFrames(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"-"}, Text: "still"})
+	s.Start()
+	for i := 0; i < 5; i++ {
+		s.RenderNextFrame()
+	}
+	if n := strings.Count(buf.String(), "\r- still"); n != 1 {
+		t.Errorf("unchanged frame written %d times, want once: %q", n, buf.String())
+	}
+	s.SetText("moving")
+	s.RenderNextFrame()
+	s.Stop()
+	if !strings.Contains(buf.String(), "\r- moving") {
+		t.Errorf("changed frame not written: %q", buf.String())
+	}
+}
+
+func TestSetCycleDuration(t *testing.T) {
+	s := New(Options{CharacterSet: []string{"a", "b", "c", "d"}})
+	s.SetCycleDuration(time.Second)
+	if s.Delay != time.Second/4 {
+		t.Errorf("4 frames: delay %v, want %v", s.Delay, time.Second/4)
+	}
+	s.UpdateCharSet([]string{"a", "b", "c", "d", "e"})
+	if s.Delay != time.Second/5 {
+		t.Errorf("5 frames: delay %v, want %v", s.Delay, time.Second/5)
+	}
+	s.UpdateSpeed(time.Millisecond)
+	s.UpdateCharSet([]string{"a", "b"})
+	if s.Delay != time.Millisecond {
+		t.Errorf("after UpdateSpeed: delay %v, want 1ms", s.Delay)
+	}
+}
+
+func TestDebugLog(t *testing.T) {
+	var mu sync.Mutex
+	var logged []string
+	s, _ := newTestSpinner(t, Options{Delay: time.Millisecond, DebugLog: func(format string, args ...interface{}) {
+		mu.Lock()
+		logged = append(logged, fmt.Sprintf(format, args...))
+		mu.Unlock()
+	}})
+	s.Start()
+	time.Sleep(10 * time.Millisecond)
+	s.Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+	all := strings.Join(logged, "\n")
+	for _, want := range []string{"start: cursor hidden=", "animation goroutine started", "stop: closed stopChan"} {
+		if !strings.Contains(all, want) {
+			t.Errorf("debug log %q doesn't mention %q", all, want)
+		}
+	}
+}
+
+func TestReconfigure(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Managed: true, Text: "before"})
+	s.Start()
+	s.RenderNextFrame()
+	buf.Reset()
+	err := s.Reconfigure(Options{
+		CharacterSet: []string{"#"},
+		Delay:        time.Second,
+		Symbol:       ">",
+		PrefixText:   "job",
+		Text:         "after",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	// erased once and redrawn once with every field changed
+	if got, want := stripANSI(buf.String()), "\r\r> job # after"; got != want {
+		t.Errorf("output %q, want %q", got, want)
+	}
+	if s.Delay != time.Second {
+		t.Errorf("delay %v, want 1s", s.Delay)
+	}
+	if err := s.Reconfigure(Options{Color: "nope", Text: "lost"}); err == nil {
+		t.Error("invalid color accepted")
+	}
+	if got := text(s); got != "after" {
+		t.Errorf("text %q after a failed Reconfigure, want %q", got, "after")
+	}
+}
+
+func TestPersistWithoutErase(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Managed: true, PersistWithoutErase: true})
+	s.Start()
+	s.RenderNextFrame()
+	buf.Reset()
+	s.StopAndPersist("*", "kept")
+
+	if strings.Contains(buf.String(), "\r") || strings.Contains(buf.String(), "\x1b[K") {
+		t.Errorf("persisted output %q erases the line", buf.String())
+	}
+	if !strings.HasSuffix(stripANSI(buf.String()), "* kept\n") {
+		t.Errorf("persisted output %q doesn't end with the line", buf.String())
+	}
+}
+
+func TestReverseUpdateCharSetRace(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Delay: time.Millisecond})
+	s.Start()
+	sets := [][]string{{"a"}, {"a", "b", "c", "d", "e", "f", "g", "h"}, {"x", "y"}}
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				if (g+i)%2 == 0 {
+					s.Reverse()
+				} else {
+					s.UpdateCharSet(sets[(g+i)%len(sets)])
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	s.Stop()
+}
+
+func TestStopWithStats(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"a", "b"}})
+	if stats := s.StopWithStats(); stats != (Stats{}) {
+		t.Errorf("stats %+v before starting, want zero", stats)
+	}
+
+	s.Start()
+	// started a minute ago on a clock set by the test
+	s.mu.Lock()
+	s.startTime = time.Now().Add(-time.Minute)
+	s.mu.Unlock()
+	for i := 0; i < 7; i++ {
+		s.RenderNextFrame()
+	}
+	stats := s.StopWithStats()
+	if stats.Frames != 7 {
+		t.Errorf("frames %d, want 7", stats.Frames)
+	}
+	if stats.Elapsed < time.Minute || stats.Elapsed > time.Minute+time.Second {
+		t.Errorf("elapsed %v, want about a minute", stats.Elapsed)
+	}
+}
+
+func TestStartWithContextCancel(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Delay: time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	s.StartWithContext(ctx)
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	waitFor(t, "the spinner to stop", func() bool { return !s.Active() })
+	// stopping again afterwards doesn't show the cursor a second time
+	s.Stop()
+
+	if n := strings.Count(buf.String(), "\x1b[?25h"); n != 1 {
+		t.Errorf("cursor shown %d times, want once: %q", n, buf.String())
+	}
+}
+
+func TestStartWithContextTimeout(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Delay: time.Millisecond})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	s.StartWithContext(ctx)
+	waitFor(t, "the spinner to stop", func() bool { return !s.Active() })
+
+	if n := strings.Count(buf.String(), "\x1b[?25h"); n != 1 {
+		t.Errorf("cursor shown %d times, want once: %q", n, buf.String())
+	}
+}
+
+func TestStartWithContextManaged(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Managed: true})
+	ctx, cancel := context.WithCancel(context.Background())
+	s.StartWithContext(ctx)
+	s.RenderNextFrame()
+	cancel()
+	waitFor(t, "the spinner to stop", func() bool { return !s.Active() })
+
+	if n := strings.Count(buf.String(), "\x1b[?25h"); n != 1 {
+		t.Errorf("cursor shown %d times, want once: %q", n, buf.String())
+	}
+}
+
+func TestStopIfNewerRun(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Managed: true})
+	s.Start()
+	s.mu.RLock()
+	stopChan := s.stopChan
+	s.mu.RUnlock()
+	s.Stop()
+	s.Start()
+	defer s.Stop()
+
+	// what's done about the first run, whether the context or the timeout,
+	// leaves the second one be
+	s.stopIf(stopChan, true, true)
+	s.timeOut(stopChan)
+	if !s.Active() {
+		t.Error("spinner stopped for a run that was over")
+	}
+	if strings.Contains(buf.String(), "timed out") {
+		t.Errorf("output %q has the timeout message", buf.String())
+	}
+}
+
+// slowWriter takes a while for every write, like a slow terminal, and
+// records when the frames were written.
+type slowWriter struct {
+	mu     sync.Mutex
+	delay  time.Duration
+	frames []time.Time
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(w.delay)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if out := string(p); strings.HasPrefix(out, "\r") && !strings.Contains(out, "\x1b[K") {
+		w.frames = append(w.frames, time.Now())
+	}
+	return len(p), nil
+}
+
+func TestFrameRateWithSlowWriter(t *testing.T) {
+	cleanEnv(t)
+	const delay = 20 * time.Millisecond
+	w := &slowWriter{delay: 8 * time.Millisecond}
+	s := New(Options{Writer: w, ForceTerminal: true, Delay: delay, CharacterSet: []string{"a", "b"}})
+	s.Start()
+	time.Sleep(300 * time.Millisecond)
+	s.Stop()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.frames) < 5 {
+		t.Fatalf("only %d frames written", len(w.frames))
+	}
+	// the time spent writing doesn't add up on top of the delay
+	interval := w.frames[len(w.frames)-1].Sub(w.frames[0]) / time.Duration(len(w.frames)-1)
+	if interval > delay+delay/2 {
+		t.Errorf("frames every %v on average, want about %v", interval, delay)
+	}
+}
+
+func TestStopAndPersistLines(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Managed: true})
+	s.Start()
+	s.RenderNextFrame()
+	buf.Reset()
+	s.StopAndPersistLines("✔", []string{"Build finished", "3 packages", "0 warnings"})
+
+	if n := strings.Count(buf.String(), "\r\x1b[K"); n != 1 {
+		t.Errorf("%d erases before the lines, want one: %q", n, buf.String())
+	}
+	got := stripANSI(buf.String()[strings.Index(buf.String(), "\r\x1b[K")+len("\r\x1b[K"):])
+	if want := "\r✔ Build finished\n  3 packages\n  0 warnings\n"; got != want {
+		t.Errorf("lines %q, want %q", got, want)
+	}
+}
+
+func TestSetTextConcurrent(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Delay: time.Millisecond})
+	s.Start()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			s.SetText(fmt.Sprintf("item %d", i))
+		}
+	}()
+	<-done
+	waitFor(t, "the last text to be drawn", func() bool { return strings.Contains(buf.String(), "item 999") })
+	s.Stop()
+}
+
+func TestManaged(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Managed: true, Delay: time.Millisecond, CharacterSet: []string{"a", "b", "c"}})
+	s.Start()
+	time.Sleep(20 * time.Millisecond)
+	if got := frames(buf.String()); len(got) != 0 {
+		t.Fatalf("frames %q drawn without RenderNextFrame", got)
+	}
+	for i := 0; i < 5; i++ {
+		s.RenderNextFrame()
+	}
+	s.Stop()
+
+	if got, want := strings.Join(frames(buf.String()), " "), "a b c a b"; got != want {
+		t.Errorf("frames %q, want %q", got, want)
+	}
+}
+
+func TestRestartStress(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Delay: time.Millisecond})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Start()
+		for i := 0; i < 1000; i++ {
+			s.Restart()
+		}
+		s.Stop()
+	}()
+	select {
+	case <-done:
+	case <-time.After(20 * time.Second):
+		t.Fatal("Restart hung")
+	}
+	if s.Active() {
+		t.Error("spinner still runs after Stop")
+	}
+}
+
+func TestActiveWhileChurning(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Delay: time.Millisecond})
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				s.Active()
+			}
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		s.Start()
+		if !s.Active() {
+			t.Fatal("not active after Start")
+		}
+		s.Stop()
+		if s.Active() {
+			t.Fatal("still active after Stop")
+		}
+	}
+	close(stop)
+	wg.Wait()
+}
+
+func TestDebugWriter(t *testing.T) {
+	debug := &syncBuffer{}
+	s, buf := newTestSpinner(t, Options{Managed: true, DebugWriter: debug})
+	s.Start()
+	s.RenderNextFrame()
+	s.PersistLine("*", "note")
+	s.Succeed("done")
+
+	if debug.String() == "" || debug.String() != buf.String() {
+		t.Errorf("debug writer got %q, want a copy of %q", debug.String(), buf.String())
+	}
+}
+
+func TestAnimatePrefix(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"a", "b", "c"}, PrefixText: "job", Text: "working"})
+	s.AnimatedSegment = AnimatePrefix
+	s.Start()
+	for i := 0; i < 3; i++ {
+		s.RenderNextFrame()
+	}
+	s.Stop()
+
+	// the character set animates in front of the prefix, while the
+	// character's own place keeps the first one
+	want := "a job a working b job a working c job a working"
+	if got := strings.Join(frames(buf.String()), " "); got != want {
+		t.Errorf("frames %q, want %q", got, want)
+	}
+}
+
+func TestWithWriterMidRun(t *testing.T) {
+	s, first := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"a", "b", "c"}})
+	s.Start()
+	s.RenderNextFrame()
+	second := &syncBuffer{}
+	s.WithWriter(second)
+	s.RenderNextFrame()
+	s.Stop()
+
+	if got := frames(first.String()); strings.Join(got, " ") != "a" {
+		t.Errorf("first writer got frames %q, want only the frame before the switch", got)
+	}
+	if !strings.HasSuffix(first.String(), "\r\x1b[K\x1b[?25h") {
+		t.Errorf("first writer %q isn't erased with its cursor shown", first.String())
+	}
+	if got := frames(second.String()); strings.Join(got, " ") != "a b" {
+		t.Errorf("second writer got frames %q, want the redraw and the next frame", got)
+	}
+	if !strings.HasSuffix(second.String(), "\x1b[?25h\r\x1b[K") {
+		t.Errorf("second writer %q isn't stopped on", second.String())
+	}
+}
+
+func TestCurrentFrame(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"a", "b", "c"}})
+	var seen []string
+	s.PostUpdate = func(s *Spinner) {
+		i, char := s.CurrentFrame()
+		seen = append(seen, fmt.Sprintf("%d:%s", i, char))
+	}
+	s.Start()
+	for i := 0; i < 4; i++ {
+		s.RenderNextFrame()
+	}
+	defer s.Stop()
+
+	if got, want := strings.Join(seen, " "), "0:a 1:b 2:c 0:a"; got != want {
+		t.Errorf("PostUpdate saw frames %q, want %q", got, want)
+	}
+	if i, char := s.CurrentFrame(); i != 0 || char != "a" {
+		t.Errorf("CurrentFrame() = %d, %q, want 0, %q", i, char, "a")
+	}
+}
+
+func TestUpdateFPS(t *testing.T) {
+	s := New(Options{})
+	if err := s.UpdateFPS(20); err != nil {
+		t.Fatal(err)
+	}
+	if s.Delay != 50*time.Millisecond {
+		t.Errorf("20 fps: delay %v, want 50ms", s.Delay)
+	}
+	for _, fps := range []float64{0, -1} {
+		if err := s.UpdateFPS(fps); err != errInvalidFPS {
+			t.Errorf("UpdateFPS(%v) = %v, want %v", fps, err, errInvalidFPS)
+		}
+	}
+	if s.Delay != 50*time.Millisecond {
+		t.Errorf("delay %v changed by an invalid frame rate", s.Delay)
+	}
+
+	if s := New(Options{Delay: time.Second, FPS: 4}); s.Delay != 250*time.Millisecond {
+		t.Errorf("FPS with Delay: delay %v, want 250ms", s.Delay)
+	}
+}
+
+func TestWriteAbove(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"-"}, Text: "working"})
+	s.Start()
+	s.RenderNextFrame()
+	buf.Reset()
+	s.WriteAbove("log line")
+	got := buf.String()
+	s.Stop()
+
+	if want := "\r\x1b[K\rlog line\n\r- working"; got != want {
+		t.Errorf("WriteAbove wrote %q, want the erase, the message and a redraw %q", got, want)
+	}
+
+	buf.Reset()
+	s.WriteAbove("after stop")
+	if got := buf.String(); got != "after stop\n" {
+		t.Errorf("WriteAbove wrote %q while stopped, want just the message", got)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Delay: time.Millisecond, CharacterSet: []string{"-"}, Timeout: 20 * time.Millisecond})
+	s.Start()
+	waitFor(t, "the timeout message", func() bool {
+		return strings.HasSuffix(stripANSI(buf.String()), " timed out\n")
+	})
+	if s.Active() {
+		t.Error("spinner still active after timing out")
+	}
+}
+
+func TestTimeoutCancelled(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Delay: time.Millisecond, CharacterSet: []string{"-"}})
+	s.SetTimeout(30 * time.Millisecond)
+	s.Start()
+	s.Succeed("done")
+	// a start after the stop doesn't time out early because of the first
+	// run either
+	s.SetTimeout(0)
+	s.Start()
+	time.Sleep(60 * time.Millisecond)
+	if !s.Active() {
+		t.Error("spinner stopped by the timeout of a run that was over")
+	}
+	s.Stop()
+	if strings.Contains(buf.String(), "timed out") {
+		t.Errorf("output %q has the timeout message", buf.String())
+	}
+}
+
+func TestStopAnyOrder(t *testing.T) {
+	tests := []struct {
+		name  string
+		calls func(s *Spinner)
+	}{
+		{"never started", func(s *Spinner) { s.Stop() }},
+		{"succeed never started", func(s *Spinner) { s.Succeed("done") }},
+		{"double stop", func(s *Spinner) { s.Start(); s.Stop(); s.Stop() }},
+		{"stop after restart", func(s *Spinner) { s.Start(); s.Restart(); s.Stop(); s.Stop() }},
+		{"stop, start, stop", func(s *Spinner) { s.Start(); s.Stop(); s.Start(); s.Stop() }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, buf := newTestSpinner(t, Options{Delay: time.Millisecond})
+			tt.calls(s)
+			if s.Active() {
+				t.Error("spinner still active")
+			}
+			buf.Reset()
+			s.Stop()
+			if buf.String() != "" {
+				t.Errorf("another Stop wrote %q", buf.String())
+			}
+		})
+	}
+}
+
+func TestSymbolSetPerSpinner(t *testing.T) {
+	a, bufA := newTestSpinner(t, Options{SuccessSymbol: "+"})
+	b, bufB := newTestSpinner(t, Options{})
+	b.SetSuccessSymbol("ok")
+	a.Start()
+	b.Start()
+	a.Succeed("first")
+	b.Succeed("second")
+	c, bufC := newTestSpinner(t, Options{})
+	c.Fail("third")
+
+	if out := stripANSI(bufA.String()); !strings.HasSuffix(out, "+ first\n") {
+		t.Errorf("first spinner wrote %q, want its own success symbol", out)
+	}
+	if out := stripANSI(bufB.String()); !strings.HasSuffix(out, "ok second\n") {
+		t.Errorf("second spinner wrote %q, want its own success symbol", out)
+	}
+	if out := stripANSI(bufC.String()); !strings.HasSuffix(out, stripANSI(logSymbols.ERROR)+" third\n") {
+		t.Errorf("third spinner wrote %q, want the default failure symbol", out)
+	}
+}
+
+func TestReverseKeepsCharSet(t *testing.T) {
+	cs := []string{"a", "b", "c"}
+	s, buf := newTestSpinner(t, Options{Managed: true, CharacterSet: cs})
+	if d := s.Direction(); d != Forward {
+		t.Errorf("direction %v, want %v", d, Forward)
+	}
+	s.Reverse()
+	if d := s.Direction(); d != Backward {
+		t.Errorf("direction %v after Reverse, want %v", d, Backward)
+	}
+	s.Start()
+	for i := 0; i < 3; i++ {
+		s.RenderNextFrame()
+	}
+	s.Stop()
+
+	if got := strings.Join(cs, " "); got != "a b c" {
+		t.Errorf("character set %q after Reverse, want it untouched", got)
+	}
+	if got := strings.Join(frames(buf.String()), " "); got != "c b a" {
+		t.Errorf("frames %q, want the character set backwards", got)
+	}
+	s.Reverse()
+	if got := strings.Join(s.Frames(), " "); got != "a b c" {
+		t.Errorf("frames %q after reversing twice, want the original order", got)
+	}
+}
+
+func TestShowElapsed(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"-"}, Text: "working"})
+	s.SetShowElapsed(true)
+	s.Start()
+	defer s.Stop()
+	for _, tt := range []struct {
+		elapsed time.Duration
+		want    string
+	}{
+		{1520 * time.Millisecond, "- working (1.5s)"},
+		{90*time.Second + 200*time.Millisecond, "- working (1m30s)"},
+	} {
+		s.mu.Lock()
+		s.startTime = time.Now().Add(-tt.elapsed)
+		s.mu.Unlock()
+		s.RenderNextFrame()
+		if got := s.LastOutput(); got != tt.want {
+			t.Errorf("after %v: frame %q, want %q", tt.elapsed, got, tt.want)
+		}
+	}
+}
+
+func TestWarnInfo(t *testing.T) {
+	for _, tt := range []struct {
+		persist func(s *Spinner, text string)
+		symbol  string
+	}{
+		{(*Spinner).Warn, logSymbols.WARNING},
+		{(*Spinner).Info, logSymbols.INFO},
+	} {
+		s, buf := newTestSpinner(t, Options{Delay: time.Hour, Text: "working"})
+		s.Start()
+		tt.persist(s, "heads up")
+		if s.Active() {
+			t.Error("spinner still active")
+		}
+		if got, want := resultLine(buf.String()), stripANSI(tt.symbol)+" heads up"; got != want {
+			t.Errorf("result line %q, want %q", got, want)
+		}
+	}
+}
+
+func TestPause(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"a", "b", "c"}})
+	s.Start()
+	s.RenderNextFrame()
+	s.Pause()
+	s.RenderNextFrame()
+	s.RenderNextFrame()
+	if !s.Active() {
+		t.Error("paused spinner isn't active")
+	}
+	s.Unpause()
+	s.RenderNextFrame()
+	s.Stop()
+
+	if got := strings.Join(frames(buf.String()), " "); got != "a b" {
+		t.Errorf("frames %q, want none drawn while paused", got)
+	}
+}
+
+func TestClear(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"a", "b"}})
+	s.Clear()
+	if buf.String() != "" {
+		t.Errorf("Clear wrote %q before Start", buf.String())
+	}
+	s.Start()
+	s.RenderNextFrame()
+	buf.Reset()
+	s.Clear()
+	if got := buf.String(); got != "\r\x1b[K" {
+		t.Errorf("Clear wrote %q, want the erase sequence", got)
+	}
+	if !s.Active() {
+		t.Error("Clear stopped the spinner")
+	}
+	s.RenderNextFrame()
+	s.Stop()
+	if got := frames(buf.String()); len(got) != 1 || got[0] != "b" {
+		t.Errorf("frames %q after Clear, want the next one", got)
+	}
+}
+
+func TestMinInterval(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"a", "b", "c"}})
+	s.MinInterval = time.Hour
+	s.Start()
+	for i := 0; i < 3; i++ {
+		s.RenderNextFrame()
+	}
+	if got := strings.Join(frames(buf.String()), " "); got != "a" {
+		t.Errorf("frames %q, want only the first one within MinInterval", got)
+	}
+	// the frames advanced meanwhile
+	if i, _ := s.CurrentFrame(); i != 2 {
+		t.Errorf("frame %d, want 2", i)
+	}
+	s.Stop()
+}
+
+func TestElapsed(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Managed: true})
+	if d := s.Elapsed(); d != 0 {
+		t.Errorf("elapsed %v before Start, want 0", d)
+	}
+	s.Start()
+	s.mu.Lock()
+	s.startTime = time.Now().Add(-time.Minute)
+	s.mu.Unlock()
+	if d := s.Elapsed(); d < time.Minute || d > time.Minute+time.Second {
+		t.Errorf("elapsed %v, want about a minute", d)
+	}
+	s.Stop()
+	if d := s.Elapsed(); d != 0 {
+		t.Errorf("elapsed %v after Stop, want 0", d)
+	}
+}
+
+// countingWriter counts the writes made to it, like the write syscalls to a
+// terminal.
+type countingWriter struct {
+	writes int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	atomic.AddInt64(&w.writes, 1)
+	return len(p), nil
+}
+
+func BenchmarkMinInterval(b *testing.B) {
+	for _, bench := range []struct {
+		name        string
+		minInterval time.Duration
+	}{
+		{"Default", defaultMinInterval},
+		{"Zero", 0},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			cleanEnv(b)
+			w := &countingWriter{}
+			// every frame changes the line, so only MinInterval holds
+			// back the writes
+			for i := 0; i < b.N; i++ {
+				s := New(Options{Writer: w, ForceTerminal: true, Delay: time.Millisecond})
+				s.MinInterval = bench.minInterval
+				s.Start()
+				time.Sleep(100 * time.Millisecond)
+				s.Stop()
+			}
+			b.ReportMetric(float64(atomic.LoadInt64(&w.writes))/float64(b.N), "writes/op")
+		})
+	}
+}
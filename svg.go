@@ -0,0 +1,69 @@
+package spintron
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// svgFontSize is the font size, in pixels, of the frames in exported SVGs.
+const svgFontSize = 16
+
+// errNoFrames is returned when attempting to export an animation without frames
+var errNoFrames = errors.New("no frames to export")
+
+// errInvalidDelay is returned when attempting to export an animation with a delay that isn't positive
+var errInvalidDelay = errors.New("delay must be positive")
+
+// ExportSVG writes an animated SVG to w that cycles through the given frames,
+// showing each one for the given delay. The animation uses SMIL so it plays
+// wherever the SVG is embedded as an image, e.g. in documentation.
+func ExportSVG(w io.Writer, frames []string, delay time.Duration) error {
+	if len(frames) == 0 {
+		return errNoFrames
+	}
+	if delay <= 0 {
+		return errInvalidDelay
+	}
+
+	columns := 1
+	for _, f := range frames {
+		if n := displayWidth(f); n > columns {
+			columns = n
+		}
+	}
+	width := columns * svgFontSize
+	height := svgFontSize * 3 / 2
+	dur := time.Duration(len(frames)) * delay
+
+	keyTimes := make([]string, len(frames))
+	for i := range frames {
+		keyTimes[i] = fmt.Sprintf("%.4f", float64(i)/float64(len(frames)))
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", width, height, width, height)
+	for i, f := range frames {
+		// each frame is only visible during its own slice of the cycle
+		values := make([]string, len(frames))
+		for j := range values {
+			values[j] = "0"
+		}
+		values[i] = "1"
+
+		fmt.Fprintf(&buf, "  <text x=\"0\" y=\"%d\" font-family=\"monospace\" font-size=\"%d\" opacity=\"0\">", svgFontSize, svgFontSize)
+		if err := xml.EscapeText(&buf, []byte(f)); err != nil {
+			return err
+		}
+		fmt.Fprintf(&buf, "<animate attributeName=\"opacity\" values=\"%s\" keyTimes=\"%s\" calcMode=\"discrete\" dur=\"%dms\" repeatCount=\"indefinite\"/></text>\n",
+			strings.Join(values, ";"), strings.Join(keyTimes, ";"), dur.Milliseconds())
+	}
+	buf.WriteString("</svg>\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,61 @@
+package spintron
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportSVG(t *testing.T) {
+	frames := []string{"<", "-", ">", "&"}
+	var buf bytes.Buffer
+	if err := ExportSVG(&buf, frames, 80*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	var texts []string
+	durations := map[string]bool{}
+	d := xml.NewDecoder(&buf)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("invalid XML: %v", err)
+		}
+		switch tok := tok.(type) {
+		case xml.CharData:
+			if text := strings.TrimSpace(string(tok)); text != "" {
+				texts = append(texts, text)
+			}
+		case xml.StartElement:
+			for _, a := range tok.Attr {
+				if tok.Name.Local == "animate" && a.Name.Local == "dur" {
+					durations[a.Value] = true
+				}
+			}
+		}
+	}
+
+	if strings.Join(texts, "") != strings.Join(frames, "") {
+		t.Errorf("frames %q, want %q", texts, frames)
+	}
+	if len(durations) != 1 || !durations["320ms"] {
+		t.Errorf("animation durations %v, want 320ms, the delay times the number of frames", durations)
+	}
+}
+
+func TestExportSVGInvalid(t *testing.T) {
+	var buf bytes.Buffer
+	if err := ExportSVG(&buf, nil, time.Second); !errors.Is(err, errNoFrames) {
+		t.Errorf("no frames: error %v, want %v", err, errNoFrames)
+	}
+	if err := ExportSVG(&buf, []string{"-"}, 0); !errors.Is(err, errInvalidDelay) {
+		t.Errorf("no delay: error %v, want %v", err, errInvalidDelay)
+	}
+}
@@ -9,3 +9,52 @@ var Symbols = map[string]string{
 	"unicorn": `🦄`,
 	"info":    `ℹ`,
 }
+
+// SetSuccessSymbol will set the symbol Succeed prints out for this spinner
+// only, "" to go back to the default.
+func (s *Spinner) SetSuccessSymbol(symbol string) {
+	s.setStatusSymbol("success", symbol)
+}
+
+// SetFailureSymbol will set the symbol Fail, and a Timeout, print out for this
+// spinner only, "" to go back to the default.
+func (s *Spinner) SetFailureSymbol(symbol string) {
+	s.setStatusSymbol("failure", symbol)
+}
+
+// SetWarningSymbol will set the symbol Warn prints out for this spinner only,
+// "" to go back to the default.
+func (s *Spinner) SetWarningSymbol(symbol string) {
+	s.setStatusSymbol("warning", symbol)
+}
+
+// SetInfoSymbol will set the symbol Info prints out for this spinner only, ""
+// to go back to the default.
+func (s *Spinner) SetInfoSymbol(symbol string) {
+	s.setStatusSymbol("info", symbol)
+}
+
+// setStatusSymbol overrides the symbol of a status for this spinner.
+func (s *Spinner) setStatusSymbol(status string, symbol string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if symbol == "" {
+		delete(s.statusSymbols, status)
+		return
+	}
+	if s.statusSymbols == nil {
+		s.statusSymbols = make(map[string]string)
+	}
+	s.statusSymbols[status] = symbol
+}
+
+// statusSymbol returns the symbol of a status set for this spinner, or the
+// given default if there's none.
+func (s *Spinner) statusSymbol(status string, fallback string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if symbol, ok := s.statusSymbols[status]; ok {
+		return symbol
+	}
+	return fallback
+}
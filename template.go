@@ -0,0 +1,68 @@
+package spintron
+
+import (
+	"strings"
+	"text/template"
+)
+
+// frameFields are the fields a Template lays out. Each is empty when the
+// spinner doesn't show it.
+type frameFields struct {
+	Symbol  string // Symbol is the symbol of the spinner
+	Prefix  string // Prefix is the prefix text
+	Char    string // Char is the character of the current frame, or the progress bar
+	Text    string // Text is the text of the spinner
+	Suffix  string // Suffix holds the progress, rate, queue and elapsed time suffixes
+	Elapsed string // Elapsed is the time since the spinner started, e.g. "1.2s"
+}
+
+// lineBreaks keeps the output of a template on a single line, which the erase
+// couldn't clear otherwise.
+var lineBreaks = strings.NewReplacer("\r", "", "\n", " ")
+
+// parseTemplate compiles the layout of a frame, e.g. "{{.Text}} {{.Char}}".
+func parseTemplate(text string) (*template.Template, error) {
+	return template.New("spinner").Parse(text)
+}
+
+// SetTemplate will lay out every frame with the given text/template, using the
+// fields Symbol, Prefix, Char, Text, Suffix and Elapsed, e.g.
+// "{{.Text}} {{.Char}}" for the spinner after the text. The template is
+// compiled once, a template that doesn't parse is returned as an error and
+// leaves the layout as it was. An empty template goes back to the usual
+// layout.
+func (s *Spinner) SetTemplate(text string) error {
+	var tmpl *template.Template
+	if text != "" {
+		var err error
+		if tmpl, err = parseTemplate(text); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	s.template = tmpl
+	s.mu.Unlock()
+	return nil
+}
+
+// executeTemplate lays out a frame with the template, once with the styled
+// fields and once with the plain ones. ok is false if the template failed,
+// leaving the usual layout in place.
+// Caller must already hold s.lock.
+func (s *Spinner) executeTemplate(styled frameFields, plain frameFields) (outColor string, outPlain string, ok bool) {
+	var b strings.Builder
+	if err := s.template.Execute(&b, styled); err != nil {
+		s.debugf("template: %v", err)
+		return "", "", false
+	}
+	outColor = lineBreaks.Replace(b.String())
+
+	b.Reset()
+	if err := s.template.Execute(&b, plain); err != nil {
+		s.debugf("template: %v", err)
+		return "", "", false
+	}
+	outPlain = lineBreaks.Replace(b.String())
+	return outColor, outPlain, true
+}
@@ -0,0 +1,30 @@
+package spintron
+
+import "testing"
+
+func TestTemplate(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"-"}, Symbol: ">", PrefixText: "job", Text: "working", Template: "{{.Prefix}}: {{.Text}} [{{.Char}}]\n{{.Symbol}}"})
+	s.Start()
+	s.RenderNextFrame()
+	if got, want := s.LastOutput(), "job: working [-] >"; got != want {
+		t.Errorf("frame %q, want %q", got, want)
+	}
+
+	if err := s.SetTemplate("{{.Text"); err == nil {
+		t.Error("template that doesn't parse: no error")
+	}
+	s.RenderNextFrame()
+	if got, want := s.LastOutput(), "job: working [-] >"; got != want {
+		t.Errorf("frame %q after a bad template, want the layout unchanged %q", got, want)
+	}
+
+	if err := s.SetTemplate(""); err != nil {
+		t.Fatal(err)
+	}
+	s.SetText("back")
+	s.RenderNextFrame()
+	defer s.Stop()
+	if got, want := s.LastOutput(), "> job - back"; got != want {
+		t.Errorf("frame %q without a template, want the usual layout %q", got, want)
+	}
+}
@@ -0,0 +1,116 @@
+package spintron
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fatih/color"
+	"golang.org/x/term"
+)
+
+// fder is implemented by writers backed by a file descriptor, such as *os.File.
+type fder interface {
+	Fd() uintptr
+}
+
+// fileDescriptor returns the file descriptor behind the given writer, if any.
+// color.Output, which may wrap stdout without exposing it, counts as stdout.
+func fileDescriptor(w io.Writer) (uintptr, bool) {
+	if w == color.Output {
+		w = os.Stdout
+	}
+	if f, ok := w.(fder); ok {
+		return f.Fd(), true
+	}
+	return 0, false
+}
+
+// terminalWidth returns the width in columns of the terminal the writer is
+// attached to, or 0 if it can't be determined.
+func terminalWidth(w io.Writer) int {
+	fd, ok := fileDescriptor(w)
+	if !ok {
+		return 0
+	}
+	width, _, err := term.GetSize(int(fd))
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
+// watchSignals handles the signals relayed to the channel until it is closed.
+func (s *Spinner) watchSignals(c chan os.Signal) {
+	for sig := range c {
+		s.handleSignal(sig)
+	}
+}
+
+// resize updates the cached terminal width.
+func (s *Spinner) resize() {
+	s.waitForEvents()
+	s.mu.Lock()
+	s.resizeTo(terminalWidth(s.Writer))
+	s.mu.Unlock()
+}
+
+// resizeTo sets the terminal width, redrawing an automatically sized progress
+// bar right away so it doesn't wrap on a narrower terminal until the next
+// frame.
+// Caller must already hold s.lock.
+func (s *Spinner) resizeTo(width int) {
+	if width == s.termWidth {
+		return
+	}
+	s.termWidth = width
+	if s.active && !s.paused && s.BarWidthAuto && s.barShown() {
+		s.draw()
+	}
+}
+
+// suspend restores the cursor and erases the spinner before the process is
+// suspended.
+func (s *Spinner) suspend() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.active {
+		return
+	}
+	if s.hidesCursor() {
+		// makes the cursor visible
+		fmt.Fprint(s.out(), "\033[?25h")
+	}
+	s.erase()
+}
+
+// resume hides the cursor again and redraws the spinner after the process
+// was continued.
+func (s *Spinner) resume() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.active {
+		return
+	}
+	if s.hidesCursor() {
+		// hides the cursor
+		fmt.Fprint(s.out(), "\033[?25l")
+	}
+	s.draw()
+}
+
+// SaveCursor saves the current position of the cursor, so output written
+// elsewhere on the screen can be followed by RestoreCursor to get back to the
+// spinner.
+func (s *Spinner) SaveCursor() {
+	s.mu.Lock()
+	fmt.Fprint(s.out(), "\033[s")
+	s.mu.Unlock()
+}
+
+// RestoreCursor moves the cursor back to the position saved by SaveCursor.
+func (s *Spinner) RestoreCursor() {
+	s.mu.Lock()
+	fmt.Fprint(s.out(), "\033[u")
+	s.mu.Unlock()
+}
@@ -0,0 +1,106 @@
+package spintron
+
+import (
+	"io"
+	"os"
+	"testing"
+)
+
+func TestSuspendResume(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"-"}})
+	s.Start()
+	s.RenderNextFrame()
+
+	buf.Reset()
+	s.suspend()
+	if got, want := buf.String(), "\x1b[?25h\r\x1b[K"; got != want {
+		t.Errorf("suspend wrote %q, want %q", got, want)
+	}
+	buf.Reset()
+	s.resume()
+	if got, want := buf.String(), "\x1b[?25l\r-"; got != want {
+		t.Errorf("resume wrote %q, want %q", got, want)
+	}
+	s.Stop()
+
+	buf.Reset()
+	s.suspend()
+	s.resume()
+	if buf.String() != "" {
+		t.Errorf("stopped spinner wrote %q", buf.String())
+	}
+}
+
+func TestSaveRestoreCursor(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{})
+	s.SaveCursor()
+	s.RestoreCursor()
+	if got, want := buf.String(), "\x1b[s\x1b[u"; got != want {
+		t.Errorf("output %q, want %q", got, want)
+	}
+}
+
+func TestTinyTerminal(t *testing.T) {
+	tests := []struct {
+		width int
+		chars []string
+		want  string
+	}{
+		// an unknown width lays out the line as usual
+		{0, []string{"-"}, "> job - working"},
+		{1, []string{"-"}, "-"},
+		{1, []string{"<=>"}, ""},
+		{3, []string{"<=>"}, "<=>"},
+	}
+	for _, tt := range tests {
+		s, _ := newTestSpinner(t, Options{Managed: true, CharacterSet: tt.chars, Symbol: ">", PrefixText: "job", Text: "working"})
+		s.Start()
+		s.mu.Lock()
+		s.termWidth = tt.width
+		s.mu.Unlock()
+		s.RenderNextFrame()
+		if got := stripANSI(s.LastOutput()); got != tt.want {
+			t.Errorf("width %d: frame %q, want %q", tt.width, got, tt.want)
+		}
+		s.Stop()
+	}
+}
+
+func TestWriterNotTerminal(t *testing.T) {
+	cleanEnv(t)
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	var buf syncBuffer
+	for _, out := range []io.Writer{&buf, w} {
+		if isRunningInTerminal(out) {
+			t.Errorf("%T counts as a terminal", out)
+		}
+		s := New(Options{Writer: out})
+		s.Start()
+		if s.Active() {
+			t.Errorf("spinner writing to %T started", out)
+			s.Stop()
+		}
+	}
+	if buf.String() != "" {
+		t.Errorf("spinner wrote %q to a buffer", buf.String())
+	}
+}
+
+func TestEnableNonTTY(t *testing.T) {
+	cleanEnv(t)
+	var buf syncBuffer
+	s := New(Options{Writer: &buf, EnableNonTTY: true, Symbol: ">", PrefixText: "job", Text: "working \x1b[1mhard\x1b[0m"})
+	s.Start()
+	if s.Active() {
+		t.Error("spinner animating to a buffer")
+	}
+	s.Start()
+	if got, want := buf.String(), "> job working hard\n> job working hard\n"; got != want {
+		t.Errorf("output %q, want the plain line once per start %q", got, want)
+	}
+}
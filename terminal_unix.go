@@ -0,0 +1,63 @@
+//go:build !windows
+// +build !windows
+
+package spintron
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifySignals relays the terminal signals the spinner handles to the given
+// channel.
+func (s *Spinner) notifySignals(c chan os.Signal) {
+	signal.Notify(c, syscall.SIGWINCH)
+	if s.HandleSuspend {
+		signal.Notify(c, syscall.SIGTSTP, syscall.SIGCONT)
+	}
+	if s.HandleInterrupt {
+		signal.Notify(c, syscall.SIGINT, syscall.SIGTERM)
+	}
+}
+
+// stopSignals stops relaying signals and closes the channel.
+func stopSignals(c chan os.Signal) {
+	signal.Stop(c)
+	close(c)
+}
+
+// raise sends the given signal to the process itself.
+var raise = func(sig syscall.Signal) {
+	syscall.Kill(os.Getpid(), sig)
+}
+
+// handleSignal reacts to a single signal relayed by notifySignals.
+func (s *Spinner) handleSignal(sig os.Signal) {
+	switch sig {
+	case syscall.SIGWINCH:
+		s.resize()
+	case syscall.SIGTSTP:
+		// the signal was caught, so stop the process the way the default
+		// handler would have once the terminal has been cleaned up
+		s.suspend()
+		syscall.Kill(os.Getpid(), syscall.SIGSTOP)
+	case syscall.SIGCONT:
+		s.resume()
+	case syscall.SIGINT, syscall.SIGTERM:
+		// restore the terminal, then stop catching the signal and raise
+		// it again, so it ends the process the way it would have
+		s.suspend()
+		s.mu.RLock()
+		c := s.sigChan
+		s.mu.RUnlock()
+		signal.Stop(c)
+		raise(sig.(syscall.Signal))
+	}
+}
+
+// enableVirtualTerminal is only needed on Windows, terminals elsewhere
+// process escape sequences anyway.
+func enableVirtualTerminal() bool {
+	return false
+}
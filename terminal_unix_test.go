@@ -0,0 +1,40 @@
+//go:build !windows
+// +build !windows
+
+package spintron
+
+import (
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestInterruptRestoresCursor(t *testing.T) {
+	raised := make(chan syscall.Signal, 1)
+	old := raise
+	raise = func(sig syscall.Signal) { raised <- sig }
+	t.Cleanup(func() { raise = old })
+
+	s, buf := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"-"}, HideCursor: true, HandleInterrupt: true})
+	s.Start()
+	s.RenderNextFrame()
+	buf.Reset()
+	s.mu.RLock()
+	c := s.sigChan
+	s.mu.RUnlock()
+	c <- syscall.SIGINT
+
+	select {
+	case sig := <-raised:
+		if sig != syscall.SIGINT {
+			t.Errorf("raised %v, want %v", sig, syscall.SIGINT)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the signal wasn't raised again")
+	}
+	if got, want := buf.String(), "\x1b[?25h\r\x1b[K"; got != want {
+		t.Errorf("cleanup wrote %q, want the cursor shown and the line erased %q", got, want)
+	}
+	// the signals stop being relayed, stopping afterwards doesn't panic
+	s.Stop()
+}
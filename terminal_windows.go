@@ -0,0 +1,36 @@
+//go:build windows
+// +build windows
+
+package spintron
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// notifySignals is a no-op on Windows, which has no resize or suspend signals.
+func (s *Spinner) notifySignals(c chan os.Signal) {}
+
+// stopSignals closes the channel.
+func stopSignals(c chan os.Signal) {
+	close(c)
+}
+
+// handleSignal is a no-op on Windows.
+func (s *Spinner) handleSignal(sig os.Signal) {}
+
+// enableVirtualTerminal turns on the processing of escape sequences by the
+// console stdout is attached to, reporting whether it's on. It fails on older
+// consoles and when stdout isn't a console, such as when it's redirected.
+func enableVirtualTerminal() bool {
+	handle := windows.Handle(os.Stdout.Fd())
+	var mode uint32
+	if err := windows.GetConsoleMode(handle, &mode); err != nil {
+		return false
+	}
+	if mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 {
+		return true
+	}
+	return windows.SetConsoleMode(handle, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING) == nil
+}
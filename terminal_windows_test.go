@@ -0,0 +1,41 @@
+//go:build windows
+// +build windows
+
+package spintron
+
+import "testing"
+
+// withLegacyConsole makes the spinner write as if to a console that doesn't
+// process escape sequences, or to one that does, for the duration of the test.
+func withLegacyConsole(t *testing.T, legacy bool) {
+	t.Helper()
+	old := isWindowsTerminalOnWindows
+	isWindowsTerminalOnWindows = !legacy
+	t.Cleanup(func() { isWindowsTerminalOnWindows = old })
+}
+
+func TestEraseLegacyConsole(t *testing.T) {
+	withLegacyConsole(t, true)
+	s, buf := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"-"}, Text: "ok 漢字"})
+	s.HideCursor = false
+	s.Start()
+	s.RenderNextFrame()
+	buf.Reset()
+	s.Stop()
+	// one space for each of the 9 cells of the line
+	if got, want := buf.String(), "\r         \r"; got != want {
+		t.Errorf("erase wrote %q, want %q", got, want)
+	}
+}
+
+func TestEraseVirtualTerminal(t *testing.T) {
+	withLegacyConsole(t, false)
+	s, buf := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"-"}})
+	s.Start()
+	s.RenderNextFrame()
+	buf.Reset()
+	s.Stop()
+	if got, want := buf.String(), "\r\x1b[K"; got != want {
+		t.Errorf("erase wrote %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,124 @@
+package spintron
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// RuneWidth returns the number of columns a rune takes up on the terminal. It
+// is used for all width calculations and can be replaced, before any spinner
+// is started, for terminals that treat ambiguous-width characters differently.
+var RuneWidth = runewidth.RuneWidth
+
+// tabWidth is the number of spaces a tab in text is expanded to.
+const tabWidth = 4
+
+// zeroWidth holds characters that take up no space on the terminal.
+var zeroWidth = map[rune]bool{
+	'\u200b': true, // zero width space
+	'\u200c': true, // zero width non-joiner
+	'\u200d': true, // zero width joiner
+	'\u2060': true, // word joiner
+	'\ufeff': true, // zero width no-break space
+}
+
+// ansiEscape matches ANSI CSI escape sequences, such as colors.
+var ansiEscape = regexp.MustCompile("\033\\[[\x30-\x3f]*[\x20-\x2f]*[\x40-\x7e]")
+
+// stripANSI removes ANSI escape sequences from the given text.
+func stripANSI(text string) string {
+	if !strings.ContainsRune(text, '\033') {
+		return text
+	}
+	return ansiEscape.ReplaceAllString(text, "")
+}
+
+// displayWidth returns the number of columns the given text takes up on the
+// terminal. Escape sequences and control characters take up no space.
+func displayWidth(text string) int {
+	text = stripANSI(text)
+	n := 0
+	for _, r := range text {
+		if unicode.IsControl(r) || zeroWidth[r] {
+			continue
+		}
+		n += RuneWidth(r)
+	}
+	return n
+}
+
+// ellipsis marks text cut short by truncate.
+const ellipsis = "…"
+
+// truncate cuts the given text down to at most width columns, ending it with
+// an ellipsis if anything was cut. Escape sequences are dropped from text that
+// is cut.
+func truncate(text string, width int) string {
+	if displayWidth(text) <= width {
+		return text
+	}
+	if width <= 0 {
+		return ""
+	}
+	text = stripANSI(text)
+	room := width - displayWidth(ellipsis)
+	var sb strings.Builder
+	n := 0
+	for _, r := range text {
+		w := 0
+		if !unicode.IsControl(r) && !zeroWidth[r] {
+			w = RuneWidth(r)
+		}
+		if n+w > room {
+			break
+		}
+		sb.WriteRune(r)
+		n += w
+	}
+	return sb.String() + ellipsis
+}
+
+// sanitize strips control and zero-width characters from the given text so
+// the rendered width stays predictable. Tabs are expanded to spaces and ANSI
+// escape sequences are kept as they are.
+func sanitize(text string) string {
+	clean := true
+	for _, r := range text {
+		if unicode.IsControl(r) || zeroWidth[r] {
+			clean = false
+			break
+		}
+	}
+	if clean {
+		return text
+	}
+
+	var sb strings.Builder
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\033' && i+1 < len(runes) && runes[i+1] == '[':
+			// copy the escape sequence up to and including its final byte
+			j := i + 2
+			for j < len(runes) && (runes[j] < 0x40 || runes[j] > 0x7e) {
+				j++
+			}
+			if j == len(runes) {
+				i = j
+				continue
+			}
+			sb.WriteString(string(runes[i : j+1]))
+			i = j
+		case r == '\t':
+			sb.WriteString(strings.Repeat(" ", tabWidth))
+		case unicode.IsControl(r), zeroWidth[r]:
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
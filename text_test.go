@@ -0,0 +1,126 @@
+package spintron
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"plain", "plain"},
+		{"a\bb\x00c\u200bd", "abcd"},
+		{"line\none\r", "lineone"},
+		{"tab\there", "tab    here"},
+		{"\x1b[31mred\x1b[0m\x07", "\x1b[31mred\x1b[0m"},
+	}
+	for _, tt := range tests {
+		if got := sanitize(tt.text); got != tt.want {
+			t.Errorf("sanitize(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestControlCharactersInText(t *testing.T) {
+	s, _ := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"-"}, Text: "a\x07b\nc\u200bd"})
+	s.Start()
+	s.RenderNextFrame()
+	defer s.Stop()
+
+	if got := lastFrame(s); got != "- abcd" {
+		t.Errorf("frame %q, want %q", got, "- abcd")
+	}
+	// the width erased is the width of what's visible
+	s.mu.RLock()
+	width := displayWidth(s.lastOutput)
+	s.mu.RUnlock()
+	if width != 6 {
+		t.Errorf("erase width %d, want 6", width)
+	}
+}
+
+func TestANSIInText(t *testing.T) {
+	text := "\x1b[31mred\x1b[0m and \x1b[1mbold\x1b[0m"
+	if got := displayWidth(text); got != len("red and bold") {
+		t.Errorf("displayWidth(%q) = %d, want %d", text, got, len("red and bold"))
+	}
+
+	s, _ := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"-"}, Text: text})
+	s.Start()
+	s.RenderNextFrame()
+	defer s.Stop()
+	s.mu.RLock()
+	width := displayWidth(s.lastOutput)
+	s.mu.RUnlock()
+	if want := len("- red and bold"); width != want {
+		t.Errorf("erase width %d, want the visible width %d", width, want)
+	}
+}
+
+func TestCustomRuneWidth(t *testing.T) {
+	runeWidth := RuneWidth
+	defer func() { RuneWidth = runeWidth }()
+	// a terminal showing the ambiguous-width arrows two columns wide
+	RuneWidth = func(r rune) int {
+		if r == '→' {
+			return 2
+		}
+		return runeWidth(r)
+	}
+
+	if got := displayWidth("a→b"); got != 4 {
+		t.Errorf("displayWidth = %d, want 4", got)
+	}
+	if got := truncate("→→→→", 5); got != "→→…" {
+		t.Errorf("truncate = %q, want %q", got, "→→…")
+	}
+}
+
+func TestWideCharacters(t *testing.T) {
+	if got := displayWidth("ok 漢字"); got != 7 {
+		t.Errorf("displayWidth = %d, want 7", got)
+	}
+
+	s, _ := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"-"}, Text: "ok 漢字"})
+	s.Start()
+	s.RenderNextFrame()
+	defer s.Stop()
+	// the clear string on consoles without escape sequences is this many
+	// spaces, one per cell
+	s.mu.RLock()
+	width := displayWidth(s.lastOutput)
+	s.mu.RUnlock()
+	if width != 9 {
+		t.Errorf("erase width %d, want the 9 cells of %q", width, s.LastOutput())
+	}
+}
+
+func TestTruncateToWidth(t *testing.T) {
+	long := "compiling a package with a rather long name"
+	for _, width := range []int{0, 20, 40} {
+		s, _ := newTestSpinner(t, Options{Managed: true, CharacterSet: []string{"-"}, Text: long})
+		s.Start()
+		s.mu.Lock()
+		s.termWidth = width
+		s.mu.Unlock()
+		s.RenderNextFrame()
+		frame := stripANSI(s.LastOutput())
+		s.Stop()
+
+		if width == 0 {
+			if frame != "- "+long {
+				t.Errorf("unknown width: frame %q, want the whole text", frame)
+			}
+			continue
+		}
+		// the last column is left free
+		if got := displayWidth(frame); got != width-1 {
+			t.Errorf("width %d: frame %q is %d wide, want %d", width, frame, got, width-1)
+		}
+		if !strings.HasSuffix(frame, ellipsis) {
+			t.Errorf("width %d: frame %q doesn't end with %q", width, frame, ellipsis)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package spintron
+
+import "time"
+
+// Theme bundles a character set with a color and delay.
+type Theme struct {
+	CharSet []string
+	Color   string
+	Delay   time.Duration
+}
+
+// Themes which can be picked by name through the SPINNER_THEME environment
+// variable. Add to the map to make more themes available.
+var Themes = map[string]Theme{
+	"default": {CharSet: CharSets["dots2"], Color: "cyan", Delay: 100 * time.Millisecond},
+	"classic": {CharSet: CharSets["line"], Color: "white", Delay: 130 * time.Millisecond},
+	"minimal": {CharSet: CharSets["simpleDots"], Color: "white", Delay: 400 * time.Millisecond},
+	"arrows":  {CharSet: CharSets["arrow3"], Color: "green", Delay: 120 * time.Millisecond},
+	"moon":    {CharSet: CharSets["moon"], Color: "yellow", Delay: 80 * time.Millisecond},
+	"pong":    {CharSet: CharSets["pong"], Color: "magenta", Delay: 80 * time.Millisecond},
+}
+
+// applyTheme sets the character set, color and delay from the given theme,
+// leaving out the ones the theme doesn't set.
+func (s *Spinner) applyTheme(theme Theme) {
+	if len(theme.CharSet) > 0 {
+		s.chars = theme.CharSet
+	}
+
+	if theme.Color != "" {
+		s.Color(theme.Color)
+	}
+
+	if theme.Delay != 0 {
+		// the theme's delay goes with its character set, so it's only
+		// recommended, and a character set given in the options replaces it
+		s.Delay = theme.Delay
+		s.suggestedDelay = theme.Delay
+	}
+}
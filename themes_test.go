@@ -0,0 +1,28 @@
+package spintron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThemeFromEnvironment(t *testing.T) {
+	withColor(t)
+	setenv(t, "SPINNER_THEME", "classic")
+	s := New(Options{})
+
+	if !sameFrames(s.chars, CharSets["line"]) {
+		t.Errorf("character set %q, want the classic theme's %q", s.chars, CharSets["line"])
+	}
+	if s.Delay != 130*time.Millisecond {
+		t.Errorf("delay %v, want the classic theme's 130ms", s.Delay)
+	}
+	if got := s.color("x"); got != "\x1b[37mx\x1b[0m" {
+		t.Errorf("color renders %q, want the classic theme's white", got)
+	}
+
+	// explicit options still win over the theme
+	s = New(Options{Color: "red", CharacterSet: []string{"a", "b"}})
+	if !sameFrames(s.chars, []string{"a", "b"}) || s.color("x") != "\x1b[31mx\x1b[0m" {
+		t.Errorf("options didn't win over the theme")
+	}
+}
@@ -0,0 +1,138 @@
+package spintron
+
+import (
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// namedColor is one of the 16 standard terminal colors along with the RGB
+// value xterm shows it as.
+type namedColor struct {
+	attribute color.Attribute
+	r, g, b   uint8
+}
+
+// namedColors are the colors RGB and Color256 fall back to on terminals
+// without support for more colors.
+var namedColors = []namedColor{
+	{color.FgBlack, 0, 0, 0},
+	{color.FgRed, 205, 0, 0},
+	{color.FgGreen, 0, 205, 0},
+	{color.FgYellow, 205, 205, 0},
+	{color.FgBlue, 0, 0, 238},
+	{color.FgMagenta, 205, 0, 205},
+	{color.FgCyan, 0, 205, 205},
+	{color.FgWhite, 229, 229, 229},
+	{color.FgHiBlack, 127, 127, 127},
+	{color.FgHiRed, 255, 0, 0},
+	{color.FgHiGreen, 0, 255, 0},
+	{color.FgHiYellow, 255, 255, 0},
+	{color.FgHiBlue, 92, 92, 255},
+	{color.FgHiMagenta, 255, 0, 255},
+	{color.FgHiCyan, 0, 255, 255},
+	{color.FgHiWhite, 255, 255, 255},
+}
+
+// RGB will color the spinner with a 24-bit color. On terminals that don't
+// announce truecolor support through COLORTERM the nearest of the 256 colors,
+// or of the named colors, is used instead.
+func (s *Spinner) RGB(r, g, b uint8) {
+	var colorFn func(a ...interface{}) string
+	switch {
+	case truecolorSupported():
+		colorFn = color.New(38, 2, color.Attribute(r), color.Attribute(g), color.Attribute(b)).SprintFunc()
+	case color256Supported():
+		colorFn = color.New(38, 5, color.Attribute(nearest256(r, g, b))).SprintFunc()
+	default:
+		colorFn = color.New(nearestNamed(r, g, b)).SprintFunc()
+	}
+
+	s.mu.Lock()
+	s.color = colorFn
+	s.styledChars = nil
+	s.palette = nil
+	s.mu.Unlock()
+}
+
+// Color256 will color the spinner with a color of the xterm 256 color palette.
+// On terminals that don't support it the nearest named color is used instead.
+func (s *Spinner) Color256(n uint8) {
+	var colorFn func(a ...interface{}) string
+	if color256Supported() {
+		colorFn = color.New(38, 5, color.Attribute(n)).SprintFunc()
+	} else {
+		colorFn = color.New(nearestNamed(paletteRGB(n))).SprintFunc()
+	}
+
+	s.mu.Lock()
+	s.color = colorFn
+	s.styledChars = nil
+	s.palette = nil
+	s.mu.Unlock()
+}
+
+// truecolorSupported reports whether the terminal announces 24-bit colors.
+func truecolorSupported() bool {
+	colorTerm := os.Getenv("COLORTERM")
+	return colorTerm == "truecolor" || colorTerm == "24bit"
+}
+
+// color256Supported reports whether the terminal supports the 256 colors.
+func color256Supported() bool {
+	return truecolorSupported() || strings.Contains(os.Getenv("TERM"), "256color")
+}
+
+// paletteRGB returns the RGB value of a color of the xterm 256 color palette.
+func paletteRGB(n uint8) (uint8, uint8, uint8) {
+	switch {
+	case n < 16:
+		c := namedColors[n]
+		return c.r, c.g, c.b
+	case n < 232:
+		n -= 16
+		return cubeLevel(n / 36), cubeLevel(n / 6 % 6), cubeLevel(n % 6)
+	default:
+		gray := 8 + (n-232)*10
+		return gray, gray, gray
+	}
+}
+
+// cubeLevel returns the intensity of a step of the 6x6x6 color cube.
+func cubeLevel(step uint8) uint8 {
+	if step == 0 {
+		return 0
+	}
+	return 55 + step*40
+}
+
+// nearest256 returns the color of the 6x6x6 color cube, or of the grayscale
+// ramp, of the xterm 256 color palette closest to the RGB value.
+func nearest256(r, g, b uint8) uint8 {
+	best, bestDistance := uint8(16), -1
+	for n := 16; n < 256; n++ {
+		pr, pg, pb := paletteRGB(uint8(n))
+		if d := distance(r, g, b, pr, pg, pb); bestDistance < 0 || d < bestDistance {
+			best, bestDistance = uint8(n), d
+		}
+	}
+	return best
+}
+
+// nearestNamed returns the named color closest to the RGB value.
+func nearestNamed(r, g, b uint8) color.Attribute {
+	best, bestDistance := namedColors[0].attribute, -1
+	for _, c := range namedColors {
+		if d := distance(r, g, b, c.r, c.g, c.b); bestDistance < 0 || d < bestDistance {
+			best, bestDistance = c.attribute, d
+		}
+	}
+	return best
+}
+
+// distance returns the squared distance between two RGB values.
+func distance(r1, g1, b1, r2, g2, b2 uint8) int {
+	dr, dg, db := int(r1)-int(r2), int(g1)-int(g2), int(b1)-int(b2)
+	return dr*dr + dg*dg + db*db
+}
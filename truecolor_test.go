@@ -0,0 +1,43 @@
+package spintron
+
+import "testing"
+
+func TestRGB(t *testing.T) {
+	withColor(t)
+	tests := []struct {
+		colorTerm, term string
+		r, g, b         uint8
+		want            string
+	}{
+		{"truecolor", "", 255, 128, 0, "\x1b[38;2;255;128;0mx\x1b[0m"},
+		{"24bit", "", 18, 52, 86, "\x1b[38;2;18;52;86mx\x1b[0m"},
+		{"", "xterm-256color", 255, 0, 0, "\x1b[38;5;196mx\x1b[0m"},
+		{"", "xterm", 200, 10, 10, "\x1b[31mx\x1b[0m"},
+	}
+	for _, tt := range tests {
+		setenv(t, "COLORTERM", tt.colorTerm)
+		setenv(t, "TERM", tt.term)
+		s := New(Options{})
+		s.RGB(tt.r, tt.g, tt.b)
+		if got := s.color("x"); got != tt.want {
+			t.Errorf("RGB(%d, %d, %d) with COLORTERM=%q TERM=%q renders %q, want %q", tt.r, tt.g, tt.b, tt.colorTerm, tt.term, got, tt.want)
+		}
+	}
+}
+
+func TestColor256(t *testing.T) {
+	withColor(t)
+	setenv(t, "COLORTERM", "")
+	setenv(t, "TERM", "xterm-256color")
+	s := New(Options{})
+	s.Color256(208)
+	if got, want := s.color("x"), "\x1b[38;5;208mx\x1b[0m"; got != want {
+		t.Errorf("Color256(208) renders %q, want %q", got, want)
+	}
+
+	setenv(t, "TERM", "xterm")
+	s.Color256(46)
+	if got, want := s.color("x"), "\x1b[92mx\x1b[0m"; got != want {
+		t.Errorf("Color256(46) without 256 colors renders %q, want the nearest named color %q", got, want)
+	}
+}
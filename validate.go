@@ -0,0 +1,102 @@
+package spintron
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// errEmptyCharSet is returned when a character set has no frames
+var errEmptyCharSet = errors.New("character set is empty")
+
+// errUnknownCharSet is returned when there's no character set with a name
+var errUnknownCharSet = errors.New("unknown character set")
+
+// Validate checks the options for mistakes New would silently ignore and
+// returns the first problem found.
+func (o Options) Validate() error {
+	if o.Color != "" && !validColor(o.Color) {
+		return fmt.Errorf("color %q: %w", o.Color, errInvalidColor)
+	}
+
+	for _, a := range o.TextAttributes {
+		if !validColor(a) {
+			return fmt.Errorf("text attribute %q: %w", a, errInvalidColor)
+		}
+	}
+
+	if o.SymbolColor != "" && !validColor(o.SymbolColor) {
+		return fmt.Errorf("symbol color %q: %w", o.SymbolColor, errInvalidColor)
+	}
+
+	if o.PrefixColor != "" && !validColor(o.PrefixColor) {
+		return fmt.Errorf("prefix color %q: %w", o.PrefixColor, errInvalidColor)
+	}
+
+	if o.TextColor != "" && !validColor(o.TextColor) {
+		return fmt.Errorf("text color %q: %w", o.TextColor, errInvalidColor)
+	}
+
+	if o.Template != "" {
+		if _, err := parseTemplate(o.Template); err != nil {
+			return err
+		}
+	}
+
+	if o.CharacterSet != nil {
+		if err := validateCharSet(o.CharacterSet); err != nil {
+			return err
+		}
+	}
+
+	if o.CharacterSetName != "" {
+		if _, ok := CharSets[o.CharacterSetName]; !ok {
+			return fmt.Errorf("character set %q: %w", o.CharacterSetName, errUnknownCharSet)
+		}
+	}
+
+	if o.Delay < 0 {
+		return fmt.Errorf("delay %s is negative", o.Delay)
+	}
+
+	if o.FPS < 0 || math.IsNaN(o.FPS) || math.IsInf(o.FPS, 0) {
+		return fmt.Errorf("fps %g: %w", o.FPS, errInvalidFPS)
+	}
+
+	if o.Timeout < 0 {
+		return fmt.Errorf("timeout %s is negative", o.Timeout)
+	}
+
+	if o.Padding < 0 {
+		return fmt.Errorf("padding %d is negative", o.Padding)
+	}
+
+	if o.Indent < 0 {
+		return fmt.Errorf("indent %d is negative", o.Indent)
+	}
+	return nil
+}
+
+// validateCharSet checks that the character set has frames and that none of
+// them would break the line.
+func validateCharSet(cs []string) error {
+	if len(cs) == 0 {
+		return errEmptyCharSet
+	}
+	for i, frame := range cs {
+		if strings.ContainsAny(frame, "\r\n") {
+			return fmt.Errorf("frame %d of the character set contains a line break", i)
+		}
+	}
+	return nil
+}
+
+// NewStrict validates the options and provides a pointer to an instance of
+// Spinner with them, or returns the problem found.
+func NewStrict(options Options) (*Spinner, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	return New(options), nil
+}
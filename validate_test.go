@@ -0,0 +1,84 @@
+package spintron
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		err     error
+	}{
+		{"valid", Options{Color: "red", CharacterSet: []string{"a"}, Delay: time.Second}, nil},
+		{"bad color", Options{Color: "nope"}, errInvalidColor},
+		{"bad text attribute", Options{TextAttributes: []string{"bold", "nope"}}, errInvalidColor},
+		{"empty character set", Options{CharacterSet: []string{}}, errEmptyCharSet},
+		{"unknown character set", Options{CharacterSetName: "nope"}, errUnknownCharSet},
+		{"bad frame rate", Options{FPS: -1}, errInvalidFPS},
+	}
+	for _, tt := range tests {
+		if err := tt.options.Validate(); !errors.Is(err, tt.err) {
+			t.Errorf("%s: error %v, want %v", tt.name, err, tt.err)
+		}
+	}
+
+	for _, options := range []Options{
+		{CharacterSet: []string{"a", "b\n"}},
+		{Delay: -time.Second},
+		{Timeout: -time.Second},
+		{Padding: -1},
+		{Indent: -1},
+	} {
+		if err := options.Validate(); err == nil {
+			t.Errorf("%+v: no error", options)
+		}
+	}
+}
+
+func TestNewStrict(t *testing.T) {
+	if s, err := NewStrict(Options{Color: "nope"}); s != nil || !errors.Is(err, errInvalidColor) {
+		t.Errorf("bad color: spinner %v, error %v", s, err)
+	}
+	if s, err := NewStrict(Options{Color: "red"}); s == nil || err != nil {
+		t.Errorf("valid options: spinner %v, error %v", s, err)
+	}
+}
+
+func TestEmptyCharSet(t *testing.T) {
+	s, buf := newTestSpinner(t, Options{Delay: time.Millisecond, CharacterSet: []string{"-"}})
+	for _, cs := range [][]string{nil, {}} {
+		if err := s.UpdateCharSetChecked(cs); !errors.Is(err, errEmptyCharSet) {
+			t.Errorf("UpdateCharSetChecked(%q) = %v, want %v", cs, err, errEmptyCharSet)
+		}
+	}
+	if got := s.Frames(); len(got) != 1 || got[0] != "-" {
+		t.Errorf("frames %q after rejected character sets, want the old one", got)
+	}
+
+	// still animated at the pace of Delay without a character to draw,
+	// rather than in a busy loop
+	var mu sync.Mutex
+	updates := 0
+	s.PostUpdate = func(s *Spinner) {
+		mu.Lock()
+		updates++
+		mu.Unlock()
+	}
+	s.UpdateCharSet(nil)
+	s.Start()
+	time.Sleep(30 * time.Millisecond)
+	s.Stop()
+	mu.Lock()
+	defer mu.Unlock()
+	if updates > 60 {
+		t.Errorf("%d updates in 30ms with a 1ms delay", updates)
+	}
+	if strings.Contains(buf.String(), "-") {
+		t.Errorf("output %q has a character of the old set", buf.String())
+	}
+}